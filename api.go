@@ -22,18 +22,46 @@
 //	}
 package youtube_transcript_api
 
+import (
+	"context"
+	"fmt"
+)
+
 // YouTubeTranscriptApi 主要的 API 接口
 type YouTubeTranscriptApi struct {
-	fetcher *TranscriptListFetcher
+	fetcher     *TranscriptListFetcher
+	options     *apiOptions
+	proxyConfig ProxyConfig
+	opts        []Option
 }
 
 // NewYouTubeTranscriptApi 创建新的 YouTubeTranscriptApi 实例
 // 注意：由于 HTTPClient 不是线程安全的，在多线程环境中，每个线程需要创建独立的实例
-func NewYouTubeTranscriptApi(proxyConfig ProxyConfig) (*YouTubeTranscriptApi, error) {
+func NewYouTubeTranscriptApi(proxyConfig ProxyConfig, opts ...Option) (*YouTubeTranscriptApi, error) {
+	options := newAPIOptions(opts)
+	if conflict := options.sharedTransportConflict(proxyConfig); conflict != "" {
+		return nil, fmt.Errorf("%w: %s", ErrSharedTransportConflict, conflict)
+	}
+
 	httpClient, err := NewHTTPClient()
 	if err != nil {
 		return nil, err
 	}
+	httpClient.HTTP1Only = options.http1Only
+	httpClient.MaxRetries = options.maxRetries
+	if options.retryBackoff > 0 {
+		httpClient.RetryBackoff = options.retryBackoff
+	}
+	httpClient.RetryIf = options.retryIf
+	httpClient.MaxRetryBackoff = options.maxRetryBackoff
+	httpClient.RetryBudget = options.retryBudget
+	httpClient.ReadTimeout = options.readTimeout
+	httpClient.SharedTransport = options.sharedTransport
+	httpClient.DialTimeout = options.dialTimeout
+	httpClient.TLSHandshakeTimeout = options.tlsTimeout
+	httpClient.ResponseHeaderTimeout = options.headerTimeout
+	httpClient.LocalAddr = options.localAddr
+	setJarCookies(httpClient.Jar, options.cookies)
 
 	// 设置默认请求头
 	httpClient.Headers["Accept-Language"] = "en-US"
@@ -46,33 +74,185 @@ func NewYouTubeTranscriptApi(proxyConfig ProxyConfig) (*YouTubeTranscriptApi, er
 	}
 
 	fetcher := NewTranscriptListFetcher(httpClient, proxyConfig)
+	fetcher.options = options
 
 	return &YouTubeTranscriptApi{
-		fetcher: fetcher,
+		fetcher:     fetcher,
+		options:     options,
+		proxyConfig: proxyConfig,
+		opts:        opts,
 	}, nil
 }
 
 // Fetch 获取单个视频的字幕
 // 这是调用 list().find_transcript(languages).fetch(preserve_formatting) 的快捷方式
 func (api *YouTubeTranscriptApi) Fetch(videoID string, languages []string, preserveFormatting bool) (*FetchedTranscript, error) {
+	return api.FetchContext(context.Background(), videoID, languages, preserveFormatting)
+}
+
+// FetchContext 获取单个视频的字幕，ctx 取消时中止请求
+func (api *YouTubeTranscriptApi) FetchContext(ctx context.Context, videoID string, languages []string, preserveFormatting bool) (*FetchedTranscript, error) {
+	transcript, _, err := api.fetchWithList(ctx, videoID, languages, preserveFormatting, (*TranscriptList).FindTranscript)
+	return transcript, err
+}
+
+// FetchManual 与 Fetch 相同，但只使用手动创建的字幕，没有时返回 NoTranscriptFound
+func (api *YouTubeTranscriptApi) FetchManual(videoID string, languages []string, preserveFormatting bool) (*FetchedTranscript, error) {
+	return api.FetchManualContext(context.Background(), videoID, languages, preserveFormatting)
+}
+
+// FetchManualContext 与 FetchManual 相同，ctx 取消时中止请求
+func (api *YouTubeTranscriptApi) FetchManualContext(ctx context.Context, videoID string, languages []string, preserveFormatting bool) (*FetchedTranscript, error) {
+	transcript, _, err := api.fetchWithList(ctx, videoID, languages, preserveFormatting, (*TranscriptList).FindManuallyCreatedTranscript)
+	return transcript, err
+}
+
+// FetchGenerated 与 Fetch 相同，但只使用自动生成的字幕，没有时返回 NoTranscriptFound
+func (api *YouTubeTranscriptApi) FetchGenerated(videoID string, languages []string, preserveFormatting bool) (*FetchedTranscript, error) {
+	return api.FetchGeneratedContext(context.Background(), videoID, languages, preserveFormatting)
+}
+
+// FetchGeneratedContext 与 FetchGenerated 相同，ctx 取消时中止请求
+func (api *YouTubeTranscriptApi) FetchGeneratedContext(ctx context.Context, videoID string, languages []string, preserveFormatting bool) (*FetchedTranscript, error) {
+	transcript, _, err := api.fetchWithList(ctx, videoID, languages, preserveFormatting, (*TranscriptList).FindGeneratedTranscript)
+	return transcript, err
+}
+
+// FetchWithList 与 Fetch 相同，同时返回获取过程中得到的字幕列表，避免为了展示可用语言再调用一次 List
+// 如果字幕列表获取成功但查找或获取字幕失败，仍会返回字幕列表
+func (api *YouTubeTranscriptApi) FetchWithList(videoID string, languages []string, preserveFormatting bool) (*FetchedTranscript, *TranscriptList, error) {
+	return api.FetchWithListContext(context.Background(), videoID, languages, preserveFormatting)
+}
+
+// FetchWithListContext 与 FetchWithList 相同，ctx 取消时中止请求
+func (api *YouTubeTranscriptApi) FetchWithListContext(ctx context.Context, videoID string, languages []string, preserveFormatting bool) (*FetchedTranscript, *TranscriptList, error) {
+	return api.fetchWithList(ctx, videoID, languages, preserveFormatting, (*TranscriptList).FindTranscript)
+}
+
+// FetchDefault 不指定语言，获取视频的主字幕（参见 TranscriptList.DefaultTranscript），不保留 HTML 格式
+// 字幕被禁用时返回 TranscriptsDisabled，没有任何字幕轨道时返回 NoTranscriptFound
+func (api *YouTubeTranscriptApi) FetchDefault(videoID string) (*FetchedTranscript, error) {
+	return api.FetchDefaultContext(context.Background(), videoID)
+}
+
+// FetchDefaultContext 与 FetchDefault 相同，ctx 取消时中止请求
+func (api *YouTubeTranscriptApi) FetchDefaultContext(ctx context.Context, videoID string) (*FetchedTranscript, error) {
+	transcriptList, err := api.ListContext(ctx, videoID)
+	if err != nil {
+		return nil, err
+	}
+	transcript, err := transcriptList.DefaultTranscript()
+	if err != nil {
+		return nil, err
+	}
+	return transcript.FetchContext(ctx, false)
+}
+
+// FetchFormatted 获取字幕并使用 format 指定的格式化器（如 "json"、"srt"、"text"，参见 FormatterLoader）格式化，返回格式化后的字符串
+// format 为空时使用 DefaultFormat；格式名称在请求之前校验，未知格式直接返回 FormatterLoader 的错误
+func (api *YouTubeTranscriptApi) FetchFormatted(videoID string, languages []string, preserveFormatting bool, format string) (string, error) {
+	return api.FetchFormattedContext(context.Background(), videoID, languages, preserveFormatting, format)
+}
+
+// FetchFormattedContext 与 FetchFormatted 相同，ctx 取消时中止请求
+func (api *YouTubeTranscriptApi) FetchFormattedContext(ctx context.Context, videoID string, languages []string, preserveFormatting bool, format string) (string, error) {
+	formatter, err := NewFormatterLoader().Load(format)
+	if err != nil {
+		return "", err
+	}
+
+	transcript, err := api.FetchContext(ctx, videoID, languages, preserveFormatting)
+	if err != nil {
+		return "", err
+	}
+	return formatter.FormatTranscript(transcript)
+}
+
+// fetchWithList 获取字幕列表，使用 find 查找字幕（找不到时尝试 WithLanguageFallback 配置的备用语言链）并获取内容
+func (api *YouTubeTranscriptApi) fetchWithList(
+	ctx context.Context,
+	videoID string,
+	languages []string,
+	preserveFormatting bool,
+	find func(tl *TranscriptList, languageCodes []string) (*Transcript, error),
+) (*FetchedTranscript, *TranscriptList, error) {
 	if len(languages) == 0 {
-		languages = []string{"en"}
+		languages = api.options.languages()
 	}
 
-	transcriptList, err := api.List(videoID)
+	transcriptList, err := api.ListContext(ctx, videoID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	transcript, err := transcriptList.FindTranscript(languages)
+	transcript, err := find(transcriptList, languages)
+	if _, notFound := err.(*NoTranscriptFound); notFound && len(api.options.languageFallback) > 0 {
+		transcript, err = find(transcriptList, api.options.languageFallback)
+	}
 	if err != nil {
-		return nil, err
+		return nil, transcriptList, err
+	}
+
+	fetched, err := transcript.FetchContext(ctx, preserveFormatting)
+	if err != nil {
+		return nil, transcriptList, err
 	}
 
-	return transcript.Fetch(preserveFormatting)
+	return fetched, transcriptList, nil
 }
 
 // List 获取视频的可用字幕列表
+// 列表中的 Transcript 可以稍后再 Fetch（如先展示语言、用户选择后再获取），在实例的整个生命周期内有效，参见 Transcript.FetchContext
 func (api *YouTubeTranscriptApi) List(videoID string) (*TranscriptList, error) {
-	return api.fetcher.Fetch(videoID)
+	return api.ListContext(context.Background(), videoID)
+}
+
+// ListContext 获取视频的可用字幕列表，ctx 取消时中止请求
+func (api *YouTubeTranscriptApi) ListContext(ctx context.Context, videoID string) (*TranscriptList, error) {
+	return api.fetcher.FetchContext(ctx, videoID)
+}
+
+// FetchPlayerResponse 获取视频解码后的原始 Innertube player 响应，用于提取本库未解析的字段（如 microformat、streamingData、章节）
+// 返回的数据结构由 YouTube 控制，没有稳定性保证，可能随时变化；该方法不检查视频可播放性，也不要求视频有字幕
+func (api *YouTubeTranscriptApi) FetchPlayerResponse(videoID string) (map[string]interface{}, error) {
+	return api.FetchPlayerResponseContext(context.Background(), videoID)
+}
+
+// FetchPlayerResponseContext 与 FetchPlayerResponse 相同，ctx 取消时中止请求
+func (api *YouTubeTranscriptApi) FetchPlayerResponseContext(ctx context.Context, videoID string) (map[string]interface{}, error) {
+	return api.fetcher.FetchPlayerResponseContext(ctx, videoID)
+}
+
+// LastFetchStats 返回最近一次 List / Fetch 获取字幕列表时的诊断信息，例如是否触发了同意 Cookie 流程
+func (api *YouTubeTranscriptApi) LastFetchStats() FetchStats {
+	return api.fetcher.LastStats()
+}
+
+// Reset 在出错后（如 Cookie 中残留了无效的 CONSENT Cookie、连接卡住）重置实例，无需重新创建
+// 会清空的：所有 Cookie（NewYouTubeTranscriptApiWithCookiesJSON 导入的 Cookie 会重新加入）、已建立的连接（下次请求时重建 Transport）以及 LastFetchStats；
+// 会保留的：代理配置、请求头和所有 Option 设置（包括 CaptionCache 中已缓存的内容）
+func (api *YouTubeTranscriptApi) Reset() error {
+	if err := api.fetcher.httpClient.Reset(); err != nil {
+		return err
+	}
+	setJarCookies(api.fetcher.httpClient.Jar, api.options.cookies)
+	api.fetcher.stats = FetchStats{}
+	return nil
+}
+
+// Close 关闭实例：释放空闲连接（包括批量方法的 worker 建立的连接），之后通过该实例（包括由它 List 或 ListBatch 得到的 Transcript）发起的请求都返回 ErrClientClosed
+// 关闭后不能 Reset 或重新打开；已获取的 Transcript 可以通过 Transcript.Bind 改用其他实例
+func (api *YouTubeTranscriptApi) Close() {
+	api.fetcher.httpClient.Close()
+}
+
+// clone 以相同的代理配置和选项创建一个独立的实例（拥有各自的 HTTPClient、Cookie 和请求状态），用于并发场景
+// 克隆实例与 api 共享 Transport（连接池），api Close 后克隆实例（包括由它 List 得到的 Transcript）同样返回 ErrClientClosed
+func (api *YouTubeTranscriptApi) clone() (*YouTubeTranscriptApi, error) {
+	worker, err := NewYouTubeTranscriptApi(api.proxyConfig, api.opts...)
+	if err != nil {
+		return nil, err
+	}
+	worker.fetcher.httpClient.parent = api.fetcher.httpClient
+	return worker, nil
 }
@@ -1,9 +1,26 @@
 package youtube_transcript_api
 
 import (
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"crypto/tls"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 // Test video IDs for different scenarios
@@ -537,6 +554,48 @@ func TestFormatterLoader(t *testing.T) {
 		}
 	})
 
+	t.Run("Load formatter aliases", func(t *testing.T) {
+		aliases := map[string]interface{}{
+			"vtt":    &WebVTTFormatter{},
+			"subrip": &SRTFormatter{},
+			"txt":    &TextFormatter{},
+			"JSON":   &JSONFormatter{},
+			"SRT":    &SRTFormatter{},
+			"Vtt":    &WebVTTFormatter{},
+		}
+		for name, expected := range aliases {
+			formatter, err := loader.Load(name)
+			if err != nil {
+				t.Errorf("Failed to load formatter alias %s: %v", name, err)
+				continue
+			}
+			if got, want := fmt.Sprintf("%T", formatter), fmt.Sprintf("%T", expected); got != want {
+				t.Errorf("Expected alias %s to load %s, got %s", name, want, got)
+			}
+		}
+	})
+
+	t.Run("Override default formatter", func(t *testing.T) {
+		custom, err := NewFormatterLoaderWithDefault("SRT")
+		if err != nil {
+			t.Fatalf("Failed to create loader with default: %v", err)
+		}
+		formatter, err := custom.Load("")
+		if err != nil {
+			t.Fatalf("Failed to load default formatter: %v", err)
+		}
+		if _, ok := formatter.(*SRTFormatter); !ok {
+			t.Errorf("Expected default SRTFormatter, got %T", formatter)
+		}
+
+		if err := custom.SetDefault("unsupported"); err == nil {
+			t.Error("Expected error for unsupported default format")
+		}
+		if custom.Default() != "srt" {
+			t.Errorf("Failed SetDefault should keep the previous default, got %s", custom.Default())
+		}
+	})
+
 	t.Run("Load unsupported formatter", func(t *testing.T) {
 		_, err := loader.Load("unsupported")
 		if err == nil {
@@ -673,6 +732,33 @@ func TestProxyConfig(t *testing.T) {
 		}
 	})
 
+	t.Run("Create generic proxy config with retries", func(t *testing.T) {
+		config, err := NewGenericProxyConfigWithRetries("http://proxy.example.com:8080", "", 3)
+		if err != nil {
+			t.Fatalf("Failed to create proxy config: %v", err)
+		}
+		if config.RetriesWhenBlocked() != 3 {
+			t.Errorf("Expected 3 retries, got %d", config.RetriesWhenBlocked())
+		}
+
+		blocked := NewRequestBlocked(testVideoID).WithProxyConfig(config)
+		if blocked.proxyConfig == nil {
+			t.Error("Expected RequestBlocked to carry the generic proxy config")
+		}
+
+		plain, err := NewGenericProxyConfig("http://proxy.example.com:8080", "")
+		if err != nil {
+			t.Fatalf("Failed to create proxy config: %v", err)
+		}
+		if plain.RetriesWhenBlocked() != 0 {
+			t.Errorf("Expected no retries by default, got %d", plain.RetriesWhenBlocked())
+		}
+
+		if _, err := NewGenericProxyConfigWithRetries("http://proxy.example.com:8080", "", -1); err == nil {
+			t.Error("Expected error for negative retries")
+		}
+	})
+
 	t.Run("Create Webshare proxy config", func(t *testing.T) {
 		// Note: NewWebshareProxyConfig internally calls NewGenericProxyConfig("", "")
 		// which should fail, but it seems the code allows this for Webshare.
@@ -870,3 +956,3750 @@ func TestMultipleVideos(t *testing.T) {
 		})
 	}
 }
+
+// newTestFetchedTranscript builds an offline transcript fixture
+func newTestFetchedTranscript() *FetchedTranscript {
+	return &FetchedTranscript{
+		Title:        "Test Video",
+		VideoID:      testVideoID,
+		Language:     "English",
+		LanguageCode: "en",
+		Snippets: []FetchedTranscriptSnippet{
+			{Text: "Hello there", Start: 0, Duration: 1.5},
+			{Text: "General Kenobi", Start: 1.5, Duration: 2},
+		},
+	}
+}
+
+// TestFetchedTranscript_ConvenienceFormatters tests the To* helpers against the formatters
+func TestFetchedTranscript_ConvenienceFormatters(t *testing.T) {
+	transcript := newTestFetchedTranscript()
+	loader := NewFormatterLoader()
+
+	cases := map[string]func() (string, error){
+		"json":   transcript.ToJSON,
+		"text":   transcript.ToText,
+		"srt":    transcript.ToSRT,
+		"webvtt": transcript.ToWebVTT,
+	}
+
+	for format, convert := range cases {
+		t.Run(format, func(t *testing.T) {
+			got, err := convert()
+			if err != nil {
+				t.Fatalf("Failed to convert transcript to %s: %v", format, err)
+			}
+
+			formatter, err := loader.Load(format)
+			if err != nil {
+				t.Fatalf("Failed to load formatter %s: %v", format, err)
+			}
+			want, err := formatter.FormatTranscript(transcript)
+			if err != nil {
+				t.Fatalf("Failed to format transcript with %s: %v", format, err)
+			}
+
+			if got != want {
+				t.Errorf("Expected %s output %q, got %q", format, want, got)
+			}
+		})
+	}
+}
+
+// TestHTTP1Only tests that WithHTTP1Only disables HTTP/2 on the reused transport
+func TestHTTP1Only(t *testing.T) {
+	t.Run("Default transport", func(t *testing.T) {
+		api, err := NewYouTubeTranscriptApi(nil)
+		if err != nil {
+			t.Fatalf("Failed to create API: %v", err)
+		}
+		transport := api.fetcher.httpClient.getTransport()
+		if transport.TLSNextProto != nil {
+			t.Error("Default transport should not override TLSNextProto")
+		}
+	})
+
+	t.Run("HTTP/1.1 only transport", func(t *testing.T) {
+		api, err := NewYouTubeTranscriptApi(nil, WithHTTP1Only())
+		if err != nil {
+			t.Fatalf("Failed to create API: %v", err)
+		}
+		transport := api.fetcher.httpClient.getTransport()
+		if transport.ForceAttemptHTTP2 {
+			t.Error("ForceAttemptHTTP2 should be false")
+		}
+		if transport.TLSNextProto == nil || len(transport.TLSNextProto) != 0 {
+			t.Error("TLSNextProto should be a non-nil empty map to disable HTTP/2")
+		}
+		if transport != api.fetcher.httpClient.getTransport() {
+			t.Error("Transport should be reused across requests")
+		}
+	})
+}
+
+// TestTranscriptList_TranslationLanguages tests that the translation languages are exposed as a copy
+func TestTranscriptList_TranslationLanguages(t *testing.T) {
+	languages := []TranslationLanguage{
+		{Language: "German", LanguageCode: "de"},
+		{Language: "French", LanguageCode: "fr"},
+	}
+	transcriptList := NewTranscriptList(testVideoID, map[string]*Transcript{}, map[string]*Transcript{}, languages)
+
+	got := transcriptList.TranslationLanguages()
+	if len(got) != len(languages) {
+		t.Fatalf("Expected %d translation languages, got %d", len(languages), len(got))
+	}
+
+	got[0].LanguageCode = "xx"
+	if transcriptList.TranslationLanguages()[0].LanguageCode != "de" {
+		t.Error("Modifying the returned slice should not affect the TranscriptList")
+	}
+}
+
+// TestFormatTranscriptsIndexed tests the video ID keyed JSON output
+func TestFormatTranscriptsIndexed(t *testing.T) {
+	first := newTestFetchedTranscript()
+	second := newTestFetchedTranscript()
+	second.VideoID = altTestVideoID
+
+	transcripts := map[string]*FetchedTranscript{
+		testVideoID:    first,
+		altTestVideoID: second,
+	}
+
+	output, err := FormatTranscriptsIndexed(transcripts)
+	if err != nil {
+		t.Fatalf("Failed to format indexed transcripts: %v", err)
+	}
+
+	var decoded map[string]map[string]interface{}
+	if err := json.Unmarshal([]byte(output), &decoded); err != nil {
+		t.Fatalf("Output should be valid JSON: %v", err)
+	}
+	if len(decoded) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(decoded))
+	}
+	if decoded[altTestVideoID]["video_id"] != altTestVideoID {
+		t.Errorf("Expected entry video_id %s, got %v", altTestVideoID, decoded[altTestVideoID]["video_id"])
+	}
+	if snippets, ok := decoded[testVideoID]["snippets"].([]interface{}); !ok || len(snippets) != len(first.Snippets) {
+		t.Errorf("Expected %d snippets for %s", len(first.Snippets), testVideoID)
+	}
+
+	// Keys are sorted, so the output is reproducible
+	if strings.Index(output, `"`+altTestVideoID+`"`) > strings.Index(output, `"`+testVideoID+`"`) {
+		t.Error("Entries should be ordered by video ID")
+	}
+	again, _ := FormatTranscriptsIndexed(transcripts)
+	if again != output {
+		t.Error("Output should be stable across calls")
+	}
+}
+
+// newTestTranscript builds a Transcript whose caption URL points at the given test server
+func newTestTranscript(t *testing.T, captionURL string) *Transcript {
+	httpClient, err := NewHTTPClient()
+	if err != nil {
+		t.Fatalf("Failed to create HTTP client: %v", err)
+	}
+	return NewTranscript(httpClient, testVideoID, "Test Video", "", captionURL, "English", "en", false, nil)
+}
+
+// newFakeYouTubeServer starts a TLS server for handler and returns a transport that routes every request to it,
+// so an API created with WithSharedTransport(transport) talks to the fake server instead of YouTube
+func newFakeYouTubeServer(handler http.HandlerFunc) (*httptest.Server, *http.Transport) {
+	server := httptest.NewTLSServer(handler)
+	transport := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, network, server.Listener.Addr().String())
+		},
+	}
+	return server, transport
+}
+
+// TestTranscript_FetchCaptchaPage tests that a captcha page served instead of captions yields IpBlocked
+func TestTranscript_FetchCaptchaPage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<html><body><p>Our systems have detected unusual traffic from your computer network.</p>`+
+			`<div class="g-recaptcha" data-sitekey="abc"></div></body></html>`)
+	}))
+	defer server.Close()
+
+	_, err := newTestTranscript(t, server.URL+"/api/timedtext?v="+testVideoID).Fetch(false)
+	if _, ok := err.(*IpBlocked); !ok {
+		t.Fatalf("Expected IpBlocked error, got %T: %v", err, err)
+	}
+}
+
+// testCaptchaWordingXML is a caption body whose text happens to contain a captcha page marker
+const testCaptchaWordingXML = `<?xml version="1.0" encoding="utf-8" ?><transcript>` +
+	`<text start="0.0" dur="3.0">We saw unusual traffic from your computer network today</text>` +
+	`</transcript>`
+
+// TestTranscript_FetchCaptchaWordingInCaptions tests that caption text containing captcha phrases is not treated as a block
+func TestTranscript_FetchCaptchaWordingInCaptions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, testCaptchaWordingXML)
+	}))
+	defer server.Close()
+
+	fetched, err := newTestTranscript(t, server.URL+"/api/timedtext?v="+testVideoID).Fetch(false)
+	if err != nil {
+		t.Fatalf("Expected caption text with captcha wording to parse, got %T: %v", err, err)
+	}
+	if len(fetched.Snippets) != 1 || !strings.Contains(fetched.Snippets[0].Text, "unusual traffic") {
+		t.Errorf("Unexpected snippets: %+v", fetched.Snippets)
+	}
+}
+
+// TestHTTPClient_RetryIf tests the default and custom retry predicates
+func TestHTTPClient_RetryIf(t *testing.T) {
+	newServer := func(failures int, failStatus int) (*httptest.Server, *int) {
+		calls := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			if calls <= failures {
+				w.WriteHeader(failStatus)
+				return
+			}
+			fmt.Fprint(w, "ok")
+		}))
+		return server, &calls
+	}
+
+	newClient := func() *HTTPClient {
+		client, err := NewHTTPClient()
+		if err != nil {
+			t.Fatalf("Failed to create HTTP client: %v", err)
+		}
+		client.MaxRetries = 2
+		client.RetryBackoff = time.Millisecond
+		return client
+	}
+
+	t.Run("Default predicate retries 5xx", func(t *testing.T) {
+		server, calls := newServer(1, http.StatusServiceUnavailable)
+		defer server.Close()
+
+		resp, err := newClient().Get(server.URL)
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || *calls != 2 {
+			t.Errorf("Expected success after 2 calls, got status %d after %d calls", resp.StatusCode, *calls)
+		}
+	})
+
+	t.Run("Default predicate does not retry 404", func(t *testing.T) {
+		server, calls := newServer(1, http.StatusNotFound)
+		defer server.Close()
+
+		resp, err := newClient().Get(server.URL)
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNotFound || *calls != 1 {
+			t.Errorf("Expected a single 404 call, got status %d after %d calls", resp.StatusCode, *calls)
+		}
+	})
+
+	t.Run("Custom predicate overrides default", func(t *testing.T) {
+		server, calls := newServer(1, http.StatusNotFound)
+		defer server.Close()
+
+		client := newClient()
+		client.RetryIf = func(resp *http.Response, err error) bool {
+			return err == nil && resp.StatusCode == http.StatusNotFound
+		}
+		resp, err := client.Post(server.URL, "application/json", strings.NewReader(`{}`))
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || *calls != 2 {
+			t.Errorf("Expected success after 2 calls, got status %d after %d calls", resp.StatusCode, *calls)
+		}
+	})
+}
+
+// TestFetchedTranscript_Equal tests transcript comparison with a time tolerance
+func TestFetchedTranscript_Equal(t *testing.T) {
+	a := newTestFetchedTranscript()
+	b := newTestFetchedTranscript()
+	b.Snippets[1].Start += 0.0004
+
+	if !a.Equal(b, 0.001) {
+		t.Error("Transcripts within tolerance should be equal")
+	}
+	if a.Equal(b, 0) {
+		t.Error("Transcripts outside tolerance should not be equal")
+	}
+
+	b = newTestFetchedTranscript()
+	b.Snippets[0].Text = "Hello"
+	if a.Equal(b, 0.001) {
+		t.Error("Transcripts with different text should not be equal")
+	}
+
+	b = newTestFetchedTranscript()
+	b.LanguageCode = "de"
+	if a.Equal(b, 0.001) {
+		t.Error("Transcripts with different metadata should not be equal")
+	}
+
+	if a.Equal(nil, 0.001) {
+		t.Error("Transcript should not equal nil")
+	}
+}
+
+// testTranscriptXML is a minimal timedtext caption body
+const testTranscriptXML = `<?xml version="1.0" encoding="utf-8" ?><transcript>` +
+	`<text start="0.0" dur="1.5">Hello there</text>` +
+	`<text start="1.5" dur="2.0">General Kenobi</text>` +
+	`</transcript>`
+
+// TestFetchTranscriptByURL tests fetching a transcript directly from a caption URL
+func TestFetchTranscriptByURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, testTranscriptXML)
+	}))
+	defer server.Close()
+
+	client, err := NewHTTPClient()
+	if err != nil {
+		t.Fatalf("Failed to create HTTP client: %v", err)
+	}
+
+	t.Run("Fetch and parse", func(t *testing.T) {
+		transcript, err := FetchTranscriptByURL(context.Background(), client, server.URL+"/api/timedtext?v="+testVideoID, testVideoID, "en", false)
+		if err != nil {
+			t.Fatalf("Failed to fetch transcript by URL: %v", err)
+		}
+		if transcript.VideoID != testVideoID || transcript.LanguageCode != "en" {
+			t.Errorf("Unexpected metadata: %+v", transcript)
+		}
+		if len(transcript.Snippets) != 2 || transcript.Snippets[1].Text != "General Kenobi" {
+			t.Errorf("Unexpected snippets: %+v", transcript.Snippets)
+		}
+	})
+
+	t.Run("PO token guard", func(t *testing.T) {
+		_, err := FetchTranscriptByURL(context.Background(), client, server.URL+"/api/timedtext?v=x&exp=xpe", testVideoID, "en", false)
+		if _, ok := err.(*PoTokenRequired); !ok {
+			t.Errorf("Expected PoTokenRequired error, got %T: %v", err, err)
+		}
+	})
+
+	t.Run("Cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := FetchTranscriptByURL(ctx, client, server.URL, testVideoID, "en", false)
+		if _, ok := err.(*YouTubeRequestFailed); !ok {
+			t.Errorf("Expected YouTubeRequestFailed error, got %T: %v", err, err)
+		}
+	})
+
+	t.Run("Nil client", func(t *testing.T) {
+		_, err := FetchTranscriptByURL(context.Background(), nil, server.URL, testVideoID, "en", false)
+		if err != ErrClientClosed {
+			t.Errorf("Expected ErrClientClosed for a nil client, got %v", err)
+		}
+	})
+
+	t.Run("Closed client", func(t *testing.T) {
+		closed, err := NewHTTPClient()
+		if err != nil {
+			t.Fatalf("Failed to create HTTP client: %v", err)
+		}
+		closed.Close()
+		_, err = FetchTranscriptByURL(context.Background(), closed, server.URL, testVideoID, "en", false)
+		if err != ErrClientClosed {
+			t.Errorf("Expected ErrClientClosed for a closed client, got %v", err)
+		}
+	})
+}
+
+// newTestTranscriptList builds an offline transcript list fixture
+func newTestTranscriptList() *TranscriptList {
+	translationLanguages := []TranslationLanguage{
+		{Language: "German", LanguageCode: "de"},
+		{Language: "French", LanguageCode: "fr"},
+	}
+	manual := map[string]*Transcript{
+		"en": NewTranscript(nil, testVideoID, "Test Video", "", "https://example.com/en", "English", "en", false, translationLanguages),
+		"es": NewTranscript(nil, testVideoID, "Test Video", "", "https://example.com/es", "Spanish", "es", false, nil),
+	}
+	generated := map[string]*Transcript{
+		"en": NewTranscript(nil, testVideoID, "Test Video", "", "https://example.com/en-asr", "English (auto-generated)", "en", true, translationLanguages),
+	}
+	return NewTranscriptList(testVideoID, manual, generated, translationLanguages)
+}
+
+// TestTranscriptList_AvailableLanguages tests the ordered language listing
+func TestTranscriptList_AvailableLanguages(t *testing.T) {
+	languages := newTestTranscriptList().AvailableLanguages()
+
+	expected := []AvailableLanguage{
+		{Language: "English", LanguageCode: "en", IsTranslatable: true},
+		{Language: "Spanish", LanguageCode: "es"},
+		{Language: "English (auto-generated)", LanguageCode: "en", IsGenerated: true, IsTranslatable: true},
+	}
+	if len(languages) != len(expected) {
+		t.Fatalf("Expected %d languages, got %d", len(expected), len(languages))
+	}
+	for i := range expected {
+		if languages[i] != expected[i] {
+			t.Errorf("Language %d: expected %+v, got %+v", i, expected[i], languages[i])
+		}
+	}
+}
+
+// TestFetchedTranscript_Deduplicate tests collapsing rolling auto-generated captions
+func TestFetchedTranscript_Deduplicate(t *testing.T) {
+	transcript := newTestFetchedTranscript()
+	transcript.IsGenerated = true
+	transcript.Snippets = []FetchedTranscriptSnippet{
+		{Text: "so today we", Start: 0.0, Duration: 2.0},
+		{Text: "so today we are going", Start: 1.0, Duration: 2.5},
+		{Text: "are going to talk about", Start: 2.5, Duration: 3.0},
+		{Text: "are going to talk about", Start: 3.0, Duration: 3.5},
+		{Text: "talk about the weather", Start: 5.0, Duration: 2.0},
+		{Text: "the end", Start: 7.5, Duration: 1.0},
+	}
+
+	deduplicated := transcript.Deduplicate()
+
+	expected := []FetchedTranscriptSnippet{
+		{Text: "so today we", Start: 0.0, Duration: 2.0},
+		{Text: "are going", Start: 1.0, Duration: 2.5},
+		{Text: "to talk about", Start: 2.5, Duration: 4.0},
+		{Text: "the weather", Start: 5.0, Duration: 2.0},
+		// A single shared word is not treated as a rolling repeat
+		{Text: "the end", Start: 7.5, Duration: 1.0},
+	}
+	if len(deduplicated.Snippets) != len(expected) {
+		t.Fatalf("Expected %d snippets, got %d: %+v", len(expected), len(deduplicated.Snippets), deduplicated.Snippets)
+	}
+	for i := range expected {
+		if !deduplicated.Snippets[i].Equal(expected[i], 1e-9) {
+			t.Errorf("Snippet %d: expected %+v, got %+v", i, expected[i], deduplicated.Snippets[i])
+		}
+	}
+
+	if deduplicated.VideoID != transcript.VideoID || !deduplicated.IsGenerated {
+		t.Error("Deduplicate should preserve metadata")
+	}
+	if transcript.Snippets[1].Text != "so today we are going" {
+		t.Error("Deduplicate should not modify the original transcript")
+	}
+}
+
+// TestWithDefaultLanguages tests the configurable default language list
+func TestWithDefaultLanguages(t *testing.T) {
+	api, err := NewYouTubeTranscriptApi(nil)
+	if err != nil {
+		t.Fatalf("Failed to create API: %v", err)
+	}
+	if got := api.options.languages(); len(got) != 1 || got[0] != "en" {
+		t.Errorf("Expected package default [en], got %v", got)
+	}
+
+	api, err = NewYouTubeTranscriptApi(nil, WithDefaultLanguages([]string{"de", "en"}))
+	if err != nil {
+		t.Fatalf("Failed to create API: %v", err)
+	}
+	if got := api.options.languages(); len(got) != 2 || got[0] != "de" || got[1] != "en" {
+		t.Errorf("Expected [de en], got %v", got)
+	}
+}
+
+// TestFetchedTranscript_HasFormatting tests detection of whitelisted formatting tags
+func TestFetchedTranscript_HasFormatting(t *testing.T) {
+	transcript := newTestFetchedTranscript()
+	if transcript.HasFormatting() {
+		t.Error("Plain transcript should not have formatting")
+	}
+
+	transcript.Snippets[1].Text = "General <I>Kenobi</I>"
+	if !transcript.HasFormatting() {
+		t.Error("Transcript with <i> tags should have formatting")
+	}
+
+	transcript.Snippets[1].Text = "<span>General</span> <img src=x>"
+	if transcript.HasFormatting() {
+		t.Error("Non-formatting tags should be ignored")
+	}
+}
+
+// TestFetchStreamBatch_Cancelled tests that a cancelled batch closes its result channel
+func TestFetchStreamBatch_Cancelled(t *testing.T) {
+	api, err := NewYouTubeTranscriptApi(nil)
+	if err != nil {
+		t.Fatalf("Failed to create API: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results := api.FetchStreamBatch(ctx, []string{testVideoID, altTestVideoID, "another"}, []string{"en"}, false, 2)
+
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case result, ok := <-results:
+			if !ok {
+				return
+			}
+			if result.Err == nil {
+				t.Errorf("Expected an error for %s after cancellation", result.VideoID)
+			}
+		case <-timeout:
+			t.Fatal("Result channel was not closed after cancellation")
+		}
+	}
+}
+
+// TestFetchStreamBatch_AbandonedConsumer tests that a consumer that stops reading early does not leak workers
+func TestFetchStreamBatch_AbandonedConsumer(t *testing.T) {
+	server, transport := newFakeYouTubeServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer server.Close()
+	before := runtime.NumGoroutine()
+
+	api, err := NewYouTubeTranscriptApi(nil)
+	if err != nil {
+		t.Fatalf("Failed to create API: %v", err)
+	}
+	api.fetcher.httpClient.transport = transport
+	videoIDs := []string{testVideoID, altTestVideoID, testVideoID, "another", "yet-another"}
+	results := api.FetchStreamBatch(context.Background(), videoIDs, []string{"en"}, false, 2)
+	for result := range results {
+		if result.Err == nil {
+			t.Errorf("Expected an error for %s from the fake server", result.VideoID)
+		}
+		break
+	}
+
+	// The remaining results must land in the buffer without a reader
+	deadline := time.Now().Add(2 * time.Second)
+	for len(results) < len(videoIDs)-1 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if len(results) != len(videoIDs)-1 {
+		t.Errorf("Expected %d buffered results, got %d", len(videoIDs)-1, len(results))
+	}
+	api.Close()
+	waitForGoroutines(t, before)
+}
+
+// TestTextFormatterWithHeader tests the title/URL header of the text formatter
+func TestTextFormatterWithHeader(t *testing.T) {
+	transcript := newTestFetchedTranscript()
+
+	output, err := NewTextFormatterWithHeader().FormatTranscript(transcript)
+	if err != nil {
+		t.Fatalf("Failed to format transcript: %v", err)
+	}
+	expected := "# Test Video\n# https://youtu.be/" + testVideoID + "\n\nHello there\nGeneral Kenobi"
+	if output != expected {
+		t.Errorf("Expected %q, got %q", expected, output)
+	}
+
+	output, err = NewTextFormatterWithHeader().FormatTranscripts([]*FetchedTranscript{transcript, transcript})
+	if err != nil {
+		t.Fatalf("Failed to format transcripts: %v", err)
+	}
+	if strings.Count(output, "# Test Video\n") != 2 {
+		t.Error("Header should be repeated for each transcript")
+	}
+
+	plain, _ := (&TextFormatter{}).FormatTranscript(transcript)
+	if strings.HasPrefix(plain, "#") {
+		t.Error("Plain text formatter should not emit a header")
+	}
+}
+
+// TestFetchedTranscript_ToRawDataWith tests the optional index and end fields
+func TestFetchedTranscript_ToRawDataWith(t *testing.T) {
+	transcript := newTestFetchedTranscript()
+	// Overlapping durations: the first snippet ends when the second one starts
+	transcript.Snippets[0].Duration = 3
+
+	rawData := transcript.ToRawDataWith(RawDataOptions{IncludeIndex: true, IncludeEnd: true})
+	if len(rawData) != 2 {
+		t.Fatalf("Expected 2 items, got %d", len(rawData))
+	}
+	if rawData[1]["index"] != 1 {
+		t.Errorf("Expected index 1, got %v", rawData[1]["index"])
+	}
+	if rawData[0]["end"] != 1.5 {
+		t.Errorf("Expected overlap-aware end 1.5, got %v", rawData[0]["end"])
+	}
+	if rawData[1]["end"] != 3.5 {
+		t.Errorf("Expected end 3.5, got %v", rawData[1]["end"])
+	}
+
+	plain := transcript.ToRawDataWith(RawDataOptions{})
+	if _, ok := plain[0]["index"]; ok {
+		t.Error("Index should not be included by default")
+	}
+	if _, ok := plain[0]["end"]; ok {
+		t.Error("End should not be included by default")
+	}
+}
+
+// TestProxyConfigFromEnv tests building a proxy config from environment variables
+func TestProxyConfigFromEnv(t *testing.T) {
+	for _, name := range []string{"HTTP_PROXY", "http_proxy", "HTTPS_PROXY", "https_proxy", "NO_PROXY", "no_proxy"} {
+		t.Setenv(name, "")
+	}
+
+	if config := ProxyConfigFromEnv(); config != nil {
+		t.Errorf("Expected nil config without proxy variables, got %v", config)
+	}
+
+	t.Setenv("HTTPS_PROXY", "http://proxy.example.com:3128")
+	config := ProxyConfigFromEnv()
+	if config == nil {
+		t.Fatal("Expected proxy config from HTTPS_PROXY")
+	}
+	httpURL, httpsURL := config.ToProxyURLs()
+	if httpURL != "http://proxy.example.com:3128" || httpsURL != "http://proxy.example.com:3128" {
+		t.Errorf("Unexpected proxy URLs: %s, %s", httpURL, httpsURL)
+	}
+
+	for _, noProxy := range []string{"*", "youtube.com", ".youtube.com", "localhost, *.youtube.com"} {
+		t.Setenv("NO_PROXY", noProxy)
+		if config := ProxyConfigFromEnv(); config != nil {
+			t.Errorf("Expected NO_PROXY=%q to disable the proxy", noProxy)
+		}
+	}
+
+	t.Setenv("NO_PROXY", "localhost,example.com")
+	if config := ProxyConfigFromEnv(); config == nil {
+		t.Error("Unrelated NO_PROXY entries should not disable the proxy")
+	}
+}
+
+// TestInnertubeClient_RequestHeaders tests the client-matched Innertube headers
+func TestInnertubeClient_RequestHeaders(t *testing.T) {
+	headers := InnertubeClientWeb.requestHeaders(testVideoID)
+	if headers["X-Youtube-Client-Name"] != "1" {
+		t.Errorf("Expected client name ID 1, got %q", headers["X-Youtube-Client-Name"])
+	}
+	if headers["X-Youtube-Client-Version"] != InnertubeClientWeb.Version {
+		t.Errorf("Expected client version %s, got %q", InnertubeClientWeb.Version, headers["X-Youtube-Client-Version"])
+	}
+	if headers["Referer"] != "https://www.youtube.com/watch?v="+testVideoID {
+		t.Errorf("Unexpected referer %q", headers["Referer"])
+	}
+
+	embedded := InnertubeClientEmbedded.requestHeaders(testVideoID)
+	if embedded["Referer"] != "https://www.youtube.com/embed/"+testVideoID {
+		t.Errorf("Embedded client should use the embed referer, got %q", embedded["Referer"])
+	}
+
+	custom := InnertubeClient{Name: "ANDROID", Version: "1.0", Headers: map[string]string{"Origin": "https://m.youtube.com"}}
+	if got := custom.requestHeaders(testVideoID)["Origin"]; got != "https://m.youtube.com" {
+		t.Errorf("Custom headers should override defaults, got %q", got)
+	}
+
+	api, err := NewYouTubeTranscriptApi(nil, WithInnertubeClient(InnertubeClientIOS))
+	if err != nil {
+		t.Fatalf("Failed to create API: %v", err)
+	}
+	if api.options.client().Name != "IOS" {
+		t.Errorf("Expected configured IOS client, got %s", api.options.client().Name)
+	}
+}
+
+// testWatchPageHTML is a minimal watch page containing an Innertube API key
+const testWatchPageHTML = `<html><script>ytcfg.set({"INNERTUBE_API_KEY":"testkey"});</script></html>`
+
+// testPlayerResponse is a playable Innertube player response with a single English caption track
+const testPlayerResponse = `{"playabilityStatus":{"status":"OK"},"videoDetails":{"title":"Test Video"},` +
+	`"captions":{"playerCaptionsTracklistRenderer":{"captionTracks":[` +
+	`{"baseUrl":"https://www.youtube.com/api/timedtext?v=` + testVideoID + `&lang=en","languageCode":"en","name":{"runs":[{"text":"English"}]}}]}}}`
+
+// TestEmbedFallback tests retrying an age-restricted video with the embedded player client
+func TestEmbedFallback(t *testing.T) {
+	ageRestricted := `{"playabilityStatus":{"status":"LOGIN_REQUIRED","reason":"` + string(PlayabilityFailedReasonAgeRestricted) + `"}}`
+	var mu sync.Mutex
+	var embedReferer, embedURL string
+	var embedCalls int
+	embedSucceeds := true
+	server, transport := newFakeYouTubeServer(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/watch":
+			fmt.Fprint(w, testWatchPageHTML)
+		case "/youtubei/v1/player":
+			var body struct {
+				Context struct {
+					Client     struct{ ClientName string } `json:"client"`
+					ThirdParty struct{ EmbedURL string }   `json:"thirdParty"`
+				} `json:"context"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			if body.Context.Client.ClientName != InnertubeClientEmbedded.Name {
+				fmt.Fprint(w, ageRestricted)
+				return
+			}
+			mu.Lock()
+			embedCalls++
+			embedReferer, embedURL = r.Header.Get("Referer"), body.Context.ThirdParty.EmbedURL
+			succeeds := embedSucceeds
+			mu.Unlock()
+			if succeeds {
+				fmt.Fprint(w, testPlayerResponse)
+			} else {
+				fmt.Fprint(w, ageRestricted)
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	defer server.Close()
+
+	api, err := NewYouTubeTranscriptApi(nil, WithSharedTransport(transport))
+	if err != nil {
+		t.Fatalf("Failed to create API: %v", err)
+	}
+	if _, err := api.List(testVideoID); err == nil {
+		t.Fatal("Expected AgeRestricted without WithEmbedFallback")
+	} else if _, ok := err.(*AgeRestricted); !ok {
+		t.Fatalf("Expected AgeRestricted, got %T: %v", err, err)
+	}
+	if embedCalls != 0 {
+		t.Errorf("Expected no embedded player requests without WithEmbedFallback, got %d", embedCalls)
+	}
+
+	api, err = NewYouTubeTranscriptApi(nil, WithSharedTransport(transport), WithEmbedFallback())
+	if err != nil {
+		t.Fatalf("Failed to create API: %v", err)
+	}
+	transcriptList, err := api.List(testVideoID)
+	if err != nil {
+		t.Fatalf("Expected the embedded player fallback to succeed, got %T: %v", err, err)
+	}
+	if _, err := transcriptList.FindTranscript([]string{"en"}); err != nil {
+		t.Errorf("Expected the English transcript from the fallback, got %v", err)
+	}
+	expectedEmbedURL := "https://www.youtube.com/embed/" + testVideoID
+	if embedCalls != 1 || embedReferer != expectedEmbedURL || embedURL != expectedEmbedURL {
+		t.Errorf("Unexpected embedded request: calls=%d referer=%q embedUrl=%q", embedCalls, embedReferer, embedURL)
+	}
+
+	// The original AgeRestricted error is kept when the fallback fails too
+	mu.Lock()
+	embedSucceeds = false
+	mu.Unlock()
+	if _, err := api.List(testVideoID); err == nil {
+		t.Fatal("Expected AgeRestricted when the fallback fails")
+	} else if _, ok := err.(*AgeRestricted); !ok {
+		t.Errorf("Expected AgeRestricted when the fallback fails, got %T: %v", err, err)
+	}
+}
+
+// TestRetriesWhenBlocked tests that RetriesWhenBlocked is the maximum number of attempts for a blocked request
+func TestRetriesWhenBlocked(t *testing.T) {
+	defer func(delay time.Duration) { requestBlockedRetryDelay = delay }(requestBlockedRetryDelay)
+	requestBlockedRetryDelay = time.Millisecond
+
+	botDetected := `{"playabilityStatus":{"status":"LOGIN_REQUIRED","reason":"` + string(PlayabilityFailedReasonBotDetected) + `"}}`
+	var attempts, blockedAttempts int32
+	server, transport := newFakeYouTubeServer(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/watch":
+			fmt.Fprint(w, testWatchPageHTML)
+		case "/youtubei/v1/player":
+			if atomic.AddInt32(&attempts, 1) <= atomic.LoadInt32(&blockedAttempts) {
+				fmt.Fprint(w, botDetected)
+				return
+			}
+			fmt.Fprint(w, testPlayerResponse)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	defer server.Close()
+
+	tests := []struct {
+		retries  int
+		blocked  int32
+		attempts int32
+		success  bool
+	}{
+		{0, 1, 1, false},
+		{1, 1, 1, false},
+		{3, 2, 3, true},
+		{3, 5, 3, false},
+	}
+	for _, tt := range tests {
+		atomic.StoreInt32(&attempts, 0)
+		atomic.StoreInt32(&blockedAttempts, tt.blocked)
+		proxyConfig, err := NewGenericProxyConfigWithRetries("http://proxy.invalid:8080", "", tt.retries)
+		if err != nil {
+			t.Fatalf("Failed to create proxy config: %v", err)
+		}
+		api, err := NewYouTubeTranscriptApi(proxyConfig)
+		if err != nil {
+			t.Fatalf("Failed to create API: %v", err)
+		}
+		// The fake transport has no proxy, so requests reach the fake server while the proxy config still drives retries
+		api.fetcher.httpClient.transport = transport
+
+		_, err = api.List(testVideoID)
+		if got := atomic.LoadInt32(&attempts); got != tt.attempts {
+			t.Errorf("retries=%d blocked=%d: expected %d attempts, got %d", tt.retries, tt.blocked, tt.attempts, got)
+		}
+		if tt.success && err != nil {
+			t.Errorf("retries=%d blocked=%d: expected success, got %v", tt.retries, tt.blocked, err)
+		}
+		if _, ok := err.(*RequestBlocked); !tt.success && !ok {
+			t.Errorf("retries=%d blocked=%d: expected RequestBlocked, got %T: %v", tt.retries, tt.blocked, err, err)
+		}
+	}
+}
+
+// TestFetchedTranscript_Filter tests filtering snippets by predicate
+func TestFetchedTranscript_Filter(t *testing.T) {
+	transcript := newTestFetchedTranscript()
+
+	filtered := transcript.Filter(func(s FetchedTranscriptSnippet) bool {
+		return s.Duration >= 2
+	})
+	if len(filtered.Snippets) != 1 || filtered.Snippets[0].Text != "General Kenobi" {
+		t.Errorf("Unexpected filtered snippets: %+v", filtered.Snippets)
+	}
+	if filtered.Title != transcript.Title || filtered.VideoID != transcript.VideoID {
+		t.Error("Filter should preserve metadata")
+	}
+	if len(transcript.Snippets) != 2 {
+		t.Error("Filter should not modify the original transcript")
+	}
+}
+
+// TestAssertPlayability_VideoUnplayable tests that the raw playability detail is preserved
+func TestAssertPlayability_VideoUnplayable(t *testing.T) {
+	fetcher := NewTranscriptListFetcher(nil, nil)
+	innertubeData := map[string]interface{}{
+		"playabilityStatus": map[string]interface{}{
+			"status": "UNPLAYABLE",
+			"reason": "Video unavailable",
+			"errorScreen": map[string]interface{}{
+				"playerErrorMessageRenderer": map[string]interface{}{
+					"subreason": map[string]interface{}{
+						"runs": []interface{}{
+							map[string]interface{}{"text": "The uploader has not made this video available in your country"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	err := fetcher.assertPlayability(innertubeData, testVideoID)
+	unplayable, ok := err.(*VideoUnplayable)
+	if !ok {
+		t.Fatalf("Expected VideoUnplayable error, got %T: %v", err, err)
+	}
+	if unplayable.Status != "UNPLAYABLE" {
+		t.Errorf("Expected status UNPLAYABLE, got %q", unplayable.Status)
+	}
+	if len(unplayable.SubReasons) != 1 {
+		t.Errorf("Expected 1 sub reason, got %v", unplayable.SubReasons)
+	}
+	if _, ok := unplayable.ErrorScreen["playerErrorMessageRenderer"]; !ok {
+		t.Error("Expected the raw errorScreen to be preserved")
+	}
+}
+
+// TestTranscriptPipeline tests composing built-in transforms by name
+func TestTranscriptPipeline(t *testing.T) {
+	transcript := newTestFetchedTranscript()
+	transcript.Snippets = append([]FetchedTranscriptSnippet{
+		{Text: "[Music]", Start: 0, Duration: 0},
+	}, transcript.Snippets...)
+
+	pipeline, err := NewTranscriptPipelineFromNames([]string{"strip-music", " Merge "})
+	if err != nil {
+		t.Fatalf("Failed to build pipeline: %v", err)
+	}
+
+	result := pipeline.Apply(transcript)
+	if len(result.Snippets) != 1 {
+		t.Fatalf("Expected 1 merged snippet, got %d: %+v", len(result.Snippets), result.Snippets)
+	}
+	if result.Snippets[0].Text != "Hello there General Kenobi" {
+		t.Errorf("Unexpected merged text %q", result.Snippets[0].Text)
+	}
+	if result.Snippets[0].Duration != 3.5 {
+		t.Errorf("Expected merged duration 3.5, got %v", result.Snippets[0].Duration)
+	}
+	if len(transcript.Snippets) != 3 {
+		t.Error("Pipeline should not modify the original transcript")
+	}
+
+	if _, err := NewTranscriptPipelineFromNames([]string{"unknown"}); err == nil {
+		t.Error("Expected error for unknown transform")
+	}
+}
+
+// TestTranscriptParser_MissingDuration tests that missing dur attributes are inferred
+func TestTranscriptParser_MissingDuration(t *testing.T) {
+	rawData := `<?xml version="1.0" encoding="utf-8" ?><transcript>` +
+		`<text start="0.0" dur="1.5">Hello there</text>` +
+		`<text start="1.5">General Kenobi</text>` +
+		`<text start="4.0">You are a bold one</text>` +
+		`</transcript>`
+
+	snippets, err := NewTranscriptParser(false).Parse(rawData)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	expected := []float64{1.5, 2.5, DefaultLastSnippetDuration}
+	for i, duration := range expected {
+		if snippets[i].Duration != duration {
+			t.Errorf("Snippet %d: expected duration %v, got %v", i, duration, snippets[i].Duration)
+		}
+	}
+
+	snippets, err = NewTranscriptParser(false).WithLastSnippetDuration(5).Parse(rawData)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	if snippets[2].Duration != 5 {
+		t.Errorf("Expected configured last duration 5, got %v", snippets[2].Duration)
+	}
+
+	// Snippets sharing a start use the first later start; with none left, the last snippet duration
+	snippets, err = NewTranscriptParser(false).Parse(`<?xml version="1.0" encoding="utf-8" ?><transcript>` +
+		`<text start="1.0">First line</text>` +
+		`<text start="1.0">Second line</text>` +
+		`<text start="3.0">Next</text>` +
+		`<text start="5.0">Tail one</text>` +
+		`<text start="5.0">Tail two</text>` +
+		`</transcript>`)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	expected = []float64{2, 2, 2, DefaultLastSnippetDuration, DefaultLastSnippetDuration}
+	for i, duration := range expected {
+		if snippets[i].Duration != duration {
+			t.Errorf("Shared start snippet %d: expected duration %v, got %v", i, duration, snippets[i].Duration)
+		}
+	}
+}
+
+// TestTranscriptParser_DecodeEntities tests decoding and preserving HTML entities
+func TestTranscriptParser_DecodeEntities(t *testing.T) {
+	rawData := `<?xml version="1.0" encoding="utf-8" ?><transcript>` +
+		`<text start="0.0" dur="1.0">Tom &amp;amp; Jerry&amp;#39;s</text>` +
+		`</transcript>`
+
+	snippets, err := NewTranscriptParser(false).Parse(rawData)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	if snippets[0].Text != "Tom & Jerry's" {
+		t.Errorf("Expected decoded text, got %q", snippets[0].Text)
+	}
+
+	snippets, err = NewTranscriptParser(false).WithDecodeEntities(false).Parse(rawData)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	if snippets[0].Text != "Tom &amp; Jerry&#39;s" {
+		t.Errorf("Expected raw entities to be preserved, got %q", snippets[0].Text)
+	}
+}
+
+// TestTranscriptParser_MinDuration tests dropping snippets shorter than the minimum duration
+func TestTranscriptParser_MinDuration(t *testing.T) {
+	rawData := `<?xml version="1.0" encoding="utf-8" ?><transcript>` +
+		`<text start="0.0" dur="1.5">Hello there</text>` +
+		`<text start="1.5" dur="0.02">uh</text>` +
+		`<text start="1.52" dur="0.1">General Kenobi</text>` +
+		`<text start="3.0">You are a bold one</text>` +
+		`</transcript>`
+
+	snippets, err := NewTranscriptParser(false).Parse(rawData)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	if len(snippets) != 4 {
+		t.Fatalf("Expected all 4 snippets by default, got %d", len(snippets))
+	}
+
+	snippets, err = NewTranscriptParser(false).WithMinDuration(0.1).Parse(rawData)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	// The threshold is inclusive and the last snippet uses the inferred default duration
+	expected := []string{"Hello there", "General Kenobi", "You are a bold one"}
+	if len(snippets) != len(expected) {
+		t.Fatalf("Expected %d snippets, got %d: %+v", len(expected), len(snippets), snippets)
+	}
+	for i, text := range expected {
+		if snippets[i].Text != text {
+			t.Errorf("Snippet %d: expected %q, got %q", i, text, snippets[i].Text)
+		}
+	}
+}
+
+// TestTranscriptList_FindTranscriptAutoTranslate tests the auto-translate directive
+func TestTranscriptList_FindTranscriptAutoTranslate(t *testing.T) {
+	transcriptList := newTestTranscriptList()
+
+	transcript, err := transcriptList.FindTranscript([]string{"ja", AutoTranslatePrefix + "de"})
+	if err != nil {
+		t.Fatalf("Expected translated transcript, got error: %v", err)
+	}
+	if transcript.LanguageCode != "de" || !strings.Contains(transcript.url, "tlang=de") {
+		t.Errorf("Expected German translation, got %s (%s)", transcript.LanguageCode, transcript.url)
+	}
+
+	transcript, err = transcriptList.FindTranscript([]string{AutoTranslatePrefix + "es"})
+	if err != nil {
+		t.Fatalf("Expected existing transcript, got error: %v", err)
+	}
+	if transcript.url != "https://example.com/es" {
+		t.Errorf("Expected the existing Spanish transcript, got %s", transcript.url)
+	}
+
+	if _, err := transcriptList.FindTranscript([]string{AutoTranslatePrefix + "ja"}); err == nil {
+		t.Error("Expected NoTranscriptFound for an unavailable translation language")
+	}
+}
+
+// TestFetchedTranscript_HeadTail tests truncating a transcript to its first or last snippets
+func TestFetchedTranscript_HeadTail(t *testing.T) {
+	transcript := newTestFetchedTranscript()
+
+	tests := []struct {
+		name     string
+		result   *FetchedTranscript
+		expected []string
+	}{
+		{"head 1", transcript.Head(1), []string{"Hello there"}},
+		{"head all", transcript.Head(5), []string{"Hello there", "General Kenobi"}},
+		{"head 0", transcript.Head(0), nil},
+		{"tail 1", transcript.Tail(1), []string{"General Kenobi"}},
+		{"tail negative", transcript.Tail(-1), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if len(tt.result.Snippets) != len(tt.expected) {
+				t.Fatalf("Expected %d snippets, got %d", len(tt.expected), len(tt.result.Snippets))
+			}
+			for i, text := range tt.expected {
+				if tt.result.Snippets[i].Text != text {
+					t.Errorf("Snippet %d: expected %q, got %q", i, text, tt.result.Snippets[i].Text)
+				}
+			}
+			if tt.result.VideoID != transcript.VideoID {
+				t.Error("Expected metadata to be preserved")
+			}
+		})
+	}
+}
+
+// TestTranscript_FetchEmptyBodyRetry tests that an empty 200 caption body is retried
+func TestTranscript_FetchEmptyBodyRetry(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			return
+		}
+		fmt.Fprint(w, testTranscriptXML)
+	}))
+	defer server.Close()
+
+	transcript := newTestTranscript(t, server.URL+"/api/timedtext?v="+testVideoID)
+	transcript.httpClient.RetryBackoff = time.Millisecond
+
+	fetched, err := transcript.Fetch(false)
+	if err != nil {
+		t.Fatalf("Expected retry to succeed, got error: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 requests, got %d", calls)
+	}
+	if len(fetched.Snippets) == 0 {
+		t.Error("Expected snippets after retry")
+	}
+
+	transcript.captionOptions.emptyBodyRetries = 0
+	calls = 0
+	if _, err := transcript.Fetch(false); err == nil {
+		t.Error("Expected error when empty body retries are disabled")
+	}
+}
+
+// TestTranscript_TranslateKeepsTrackParams tests that translating keeps the base track's query parameters
+func TestTranscript_TranslateKeepsTrackParams(t *testing.T) {
+	translationLanguages := []TranslationLanguage{{Language: "German", LanguageCode: "de"}}
+	baseURL := "https://www.youtube.com/api/timedtext?v=" + testVideoID + "&lang=en&kind=asr&sparams=ip,ipbits"
+	transcript := NewTranscript(nil, testVideoID, "Test Video", "", baseURL, "English (auto-generated)", "en", true, translationLanguages)
+
+	translated, err := transcript.Translate("de")
+	if err != nil {
+		t.Fatalf("Failed to translate generated transcript: %v", err)
+	}
+	if expected := baseURL + "&tlang=de"; translated.url != expected {
+		t.Errorf("Expected %s, got %s", expected, translated.url)
+	}
+
+	// An existing tlang parameter is replaced rather than duplicated
+	transcript.url = baseURL + "&tlang=fr"
+	translated, err = transcript.Translate("de")
+	if err != nil {
+		t.Fatalf("Failed to translate: %v", err)
+	}
+	if strings.Count(translated.url, "tlang=") != 1 || !strings.HasSuffix(translated.url, "&tlang=de") {
+		t.Errorf("Expected a single tlang=de parameter, got %s", translated.url)
+	}
+}
+
+// TestTranscriptList_FindTranscriptOrTranslate tests translating to the first requested language as a fallback
+func TestTranscriptList_FindTranscriptOrTranslate(t *testing.T) {
+	transcriptList := newTestTranscriptList()
+
+	transcript, err := transcriptList.FindTranscriptOrTranslate([]string{"es", "en"})
+	if err != nil || transcript.LanguageCode != "es" {
+		t.Fatalf("Expected exact match for es, got %v, %v", transcript, err)
+	}
+
+	transcript, err = transcriptList.FindTranscriptOrTranslate([]string{"fr", "ja"})
+	if err != nil {
+		t.Fatalf("Expected French translation, got error: %v", err)
+	}
+	if transcript.LanguageCode != "fr" || transcript.url != "https://example.com/en?tlang=fr" {
+		t.Errorf("Expected translation of the manual English track, got %s (%s)", transcript.LanguageCode, transcript.url)
+	}
+
+	if _, err := transcriptList.FindTranscriptOrTranslate([]string{"ja"}); err == nil {
+		t.Error("Expected error when no track can be translated to the target")
+	} else if _, ok := err.(*TranslationLanguageNotAvailable); !ok {
+		t.Errorf("Expected TranslationLanguageNotAvailable, got %T", err)
+	}
+}
+
+// TestFetchedTranscript_ReadingTime tests the word count based analytics helpers
+func TestFetchedTranscript_ReadingTime(t *testing.T) {
+	transcript := newTestFetchedTranscript()
+
+	if count := transcript.WordCount(); count != 4 {
+		t.Errorf("Expected 4 words, got %d", count)
+	}
+	if readingTime := transcript.ReadingTime(120); readingTime != 2*time.Second {
+		t.Errorf("Expected 2s reading time, got %v", readingTime)
+	}
+	if speaking := transcript.SpeakingDuration(); speaking != 3500*time.Millisecond {
+		t.Errorf("Expected 3.5s speaking duration, got %v", speaking)
+	}
+}
+
+// TestBuildTranscriptList_SchemaVariants tests caption track schemas from different client versions
+func TestBuildTranscriptList_SchemaVariants(t *testing.T) {
+	fixtures := []string{
+		"caption_tracks_kind.json",
+		"caption_tracks_vssid.json",
+		"caption_tracks_string_translatable.json",
+	}
+
+	for _, fixture := range fixtures {
+		t.Run(fixture, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("testdata", fixture))
+			if err != nil {
+				t.Fatalf("Failed to read fixture: %v", err)
+			}
+			var captionsJSON map[string]interface{}
+			if err := json.Unmarshal(data, &captionsJSON); err != nil {
+				t.Fatalf("Failed to decode fixture: %v", err)
+			}
+
+			transcriptList, err := BuildTranscriptList(nil, testVideoID, map[string]interface{}{"title": "Test Video"}, captionsJSON)
+			if err != nil {
+				t.Fatalf("Failed to build transcript list: %v", err)
+			}
+
+			manual, err := transcriptList.FindManuallyCreatedTranscript([]string{"en"})
+			if err != nil {
+				t.Fatalf("Expected a manual English track: %v", err)
+			}
+			generated, err := transcriptList.FindGeneratedTranscript([]string{"en"})
+			if err != nil {
+				t.Fatalf("Expected a generated English track: %v", err)
+			}
+			if !strings.Contains(generated.url, "kind=asr") || strings.Contains(manual.url, "kind=asr") {
+				t.Errorf("Tracks misclassified: manual=%s generated=%s", manual.url, generated.url)
+			}
+			if !manual.IsTranslatable() || !generated.IsTranslatable() {
+				t.Error("Expected both tracks to be translatable")
+			}
+		})
+	}
+}
+
+// TestLanguageName tests the embedded language name table lookup
+func TestLanguageName(t *testing.T) {
+	tests := []struct {
+		code     string
+		expected string
+		found    bool
+	}{
+		{"en", "English", true},
+		{"pt-br", "Portuguese (Brazil)", true},
+		{"zh_Hant", "Chinese (Traditional)", true},
+		{"de-AT", "German", true},
+		{"xx", "", false},
+	}
+
+	for _, tt := range tests {
+		name, found := LanguageName(tt.code)
+		if name != tt.expected || found != tt.found {
+			t.Errorf("LanguageName(%q) = %q, %v; expected %q, %v", tt.code, name, found, tt.expected, tt.found)
+		}
+	}
+}
+
+// TestTranscript_FetchCaptionCache tests that caption bodies are served from the cache
+func TestTranscript_FetchCaptionCache(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		fmt.Fprint(w, testTranscriptXML)
+	}))
+	defer server.Close()
+
+	transcript := newTestTranscript(t, server.URL+"/api/timedtext?v="+testVideoID)
+	transcript.captionOptions.cache = NewMemoryCaptionCache()
+
+	first, err := transcript.Fetch(false)
+	if err != nil {
+		t.Fatalf("Failed to fetch: %v", err)
+	}
+	second, err := transcript.Fetch(true)
+	if err != nil {
+		t.Fatalf("Failed to fetch from cache: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected 1 request, got %d", calls)
+	}
+	if !first.Equal(second, 0) {
+		t.Error("Expected cached fetch to produce the same transcript")
+	}
+}
+
+// TestInnertubeError tests mapping Innertube error envelopes to typed errors
+func TestInnertubeError(t *testing.T) {
+	decode := func(raw string) map[string]interface{} {
+		var result map[string]interface{}
+		if err := json.Unmarshal([]byte(raw), &result); err != nil {
+			t.Fatalf("Failed to decode: %v", err)
+		}
+		return result
+	}
+
+	err := innertubeError(decode(`{"error": {"code": 400, "message": "Precondition check failed.", "status": "FAILED_PRECONDITION"}}`), testVideoID)
+	requestFailed, ok := err.(*YouTubeRequestFailed)
+	if !ok {
+		t.Fatalf("Expected YouTubeRequestFailed, got %T: %v", err, err)
+	}
+	if !strings.Contains(requestFailed.Reason, "Precondition check failed.") {
+		t.Errorf("Expected the envelope message in the reason, got %q", requestFailed.Reason)
+	}
+
+	err = innertubeError(decode(`{"error": {"code": 429, "message": "Too many requests", "status": "RESOURCE_EXHAUSTED"}}`), testVideoID)
+	if _, ok := err.(*IpBlocked); !ok {
+		t.Errorf("Expected IpBlocked, got %T: %v", err, err)
+	}
+
+	if err := innertubeError(decode(`{"playabilityStatus": {"status": "OK"}}`), testVideoID); err != nil {
+		t.Errorf("Expected no error for player data, got %v", err)
+	}
+}
+
+// TestWithRetryablePlayabilityReasons tests matching reasons and subreasons for playability retries
+func TestWithRetryablePlayabilityReasons(t *testing.T) {
+	options := newAPIOptions([]Option{WithRetryablePlayabilityReasons("This video is currently unavailable")})
+
+	if !options.playabilityRetryIf(NewVideoUnplayable(testVideoID, "this video is currently unavailable", nil)) {
+		t.Error("Expected matching reason to be retryable")
+	}
+	if !options.playabilityRetryIf(NewVideoUnplayable(testVideoID, "Video unavailable", []string{"This video is currently unavailable"})) {
+		t.Error("Expected matching subreason to be retryable")
+	}
+	if options.playabilityRetryIf(NewVideoUnplayable(testVideoID, "Private video", nil)) {
+		t.Error("Expected other reasons not to be retryable")
+	}
+	if newAPIOptions(nil).playabilityRetryIf != nil {
+		t.Error("Expected no playability retries by default")
+	}
+}
+
+// TestTranscriptList_AllTranslationLanguageCodes tests the union of per-track translation languages
+func TestTranscriptList_AllTranslationLanguageCodes(t *testing.T) {
+	manual := map[string]*Transcript{
+		"en": NewTranscript(nil, testVideoID, "Test Video", "", "https://example.com/en", "English", "en", false,
+			[]TranslationLanguage{{Language: "German", LanguageCode: "de"}}),
+		"es": NewTranscript(nil, testVideoID, "Test Video", "", "https://example.com/es", "Spanish", "es", false,
+			[]TranslationLanguage{{Language: "French", LanguageCode: "fr"}, {Language: "German", LanguageCode: "de"}}),
+	}
+	transcriptList := NewTranscriptList(testVideoID, manual, map[string]*Transcript{}, nil)
+
+	codes := transcriptList.AllTranslationLanguageCodes()
+	if strings.Join(codes, ",") != "de,fr" {
+		t.Errorf("Expected [de fr], got %v", codes)
+	}
+}
+
+// TestHTTPClient_ReadTimeout tests that a stalled response body is aborted
+func TestHTTPClient_ReadTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "<")
+		w.(http.Flusher).Flush()
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client, err := NewHTTPClient()
+	if err != nil {
+		t.Fatalf("Failed to create HTTP client: %v", err)
+	}
+	client.ReadTimeout = 50 * time.Millisecond
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+
+	start := time.Now()
+	if _, err := client.readBody(resp.Body); err != ErrReadTimeout {
+		t.Errorf("Expected ErrReadTimeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Read took too long: %v", elapsed)
+	}
+}
+
+// TestTranscriptList_LanguageMatrix tests per-language track availability
+func TestTranscriptList_LanguageMatrix(t *testing.T) {
+	transcriptList := newTestTranscriptList()
+
+	matrix := transcriptList.LanguageMatrix()
+	if matrix["en"] != (LanguageAvailability{Manual: true, Generated: true}) {
+		t.Errorf("Expected en to have both tracks, got %+v", matrix["en"])
+	}
+	if matrix["es"] != (LanguageAvailability{Manual: true}) {
+		t.Errorf("Expected es to have only a manual track, got %+v", matrix["es"])
+	}
+	if codes := transcriptList.LanguageCodes(); strings.Join(codes, ",") != "en,es" {
+		t.Errorf("Expected [en es], got %v", codes)
+	}
+}
+
+// TestWebVTTFormatterWithStyle tests that STYLE and NOTE blocks precede the cues
+func TestWebVTTFormatterWithStyle(t *testing.T) {
+	formatter := NewWebVTTFormatterWithStyle("::cue {\n  color: white;\n}", []string{"Title: Test Video", "Language: en\nsecond line"})
+
+	output, err := formatter.FormatTranscript(newTestFetchedTranscript())
+	if err != nil {
+		t.Fatalf("Failed to format: %v", err)
+	}
+
+	expectedPrefix := "WEBVTT\n\n" +
+		"STYLE\n::cue {\n  color: white;\n}\n\n" +
+		"NOTE Title: Test Video\n\n" +
+		"NOTE Language: en second line\n\n" +
+		"00:00:00.000 --> 00:00:01.500\nHello there"
+	if !strings.HasPrefix(output, expectedPrefix) {
+		t.Errorf("Unexpected WebVTT output:\n%s", output)
+	}
+	if strings.Count(output, "-->") != 2 {
+		t.Errorf("Expected only the two cue timings to contain -->, got:\n%s", output)
+	}
+}
+
+// TestFetchedTranscript_Validate tests detecting malformed snippets
+func TestFetchedTranscript_Validate(t *testing.T) {
+	if problems := newTestFetchedTranscript().Validate(); len(problems) != 0 {
+		t.Errorf("Expected a clean transcript, got %v", problems)
+	}
+
+	transcript := newTestFetchedTranscript()
+	transcript.Snippets = append(transcript.Snippets,
+		FetchedTranscriptSnippet{Text: " ", Start: 3.0, Duration: 1.0},
+		FetchedTranscriptSnippet{Text: "Back in time", Start: 1.0, Duration: 0},
+		FetchedTranscriptSnippet{Text: "Negative", Start: -1.0, Duration: 1.0},
+	)
+
+	problems := transcript.Validate()
+	if len(problems) != 5 {
+		t.Errorf("Expected 5 problems, got %d: %v", len(problems), problems)
+	}
+}
+
+// TestParseSRTAndWebVTT tests round-tripping the SRT and WebVTT formatters through the parsers
+func TestParseSRTAndWebVTT(t *testing.T) {
+	transcript := newTestFetchedTranscript()
+	transcript.Snippets[1].Text = "General\nKenobi"
+
+	srt, err := NewSRTFormatter().FormatTranscript(transcript)
+	if err != nil {
+		t.Fatalf("Failed to format SRT: %v", err)
+	}
+	parsed, err := ParseSRT(strings.NewReader(srt))
+	if err != nil {
+		t.Fatalf("Failed to parse SRT: %v", err)
+	}
+	if len(parsed.Snippets) != 2 || !parsed.Snippets[1].Equal(transcript.Snippets[1], 0.001) {
+		t.Errorf("Unexpected SRT round-trip result: %+v", parsed.Snippets)
+	}
+
+	vtt, err := NewWebVTTFormatterWithStyle("::cue { color: white; }", []string{"Title: Test Video"}).FormatTranscript(transcript)
+	if err != nil {
+		t.Fatalf("Failed to format WebVTT: %v", err)
+	}
+	parsed, err = ParseWebVTT(strings.NewReader(vtt))
+	if err != nil {
+		t.Fatalf("Failed to parse WebVTT: %v", err)
+	}
+	if len(parsed.Snippets) != 2 || !parsed.Snippets[0].Equal(transcript.Snippets[0], 0.001) {
+		t.Errorf("Unexpected WebVTT round-trip result: %+v", parsed.Snippets)
+	}
+
+	parsed, err = ParseWebVTT(strings.NewReader("WEBVTT\r\n\r\nintro\r\n01:02.500 --> 01:04.000 align:start\r\nHi\r\n"))
+	if err != nil {
+		t.Fatalf("Failed to parse WebVTT with identifier and settings: %v", err)
+	}
+	if len(parsed.Snippets) != 1 || parsed.Snippets[0].Start != 62.5 || parsed.Snippets[0].Duration != 1.5 {
+		t.Errorf("Unexpected cue: %+v", parsed.Snippets)
+	}
+
+	if _, err := ParseWebVTT(strings.NewReader("1\n00:00:01,000 --> 00:00:02,000\nHi\n")); err == nil {
+		t.Error("Expected error for missing WEBVTT header")
+	}
+}
+
+// TestFetchBatch_CancelledNoLeak tests that a cancelled batch starts no videos and leaves no goroutines behind
+func TestFetchBatch_CancelledNoLeak(t *testing.T) {
+	api, err := NewYouTubeTranscriptApi(nil)
+	if err != nil {
+		t.Fatalf("Failed to create API: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	before := runtime.NumGoroutine()
+	videoIDs := []string{testVideoID, altTestVideoID, "another"}
+
+	results := api.FetchBatch(ctx, videoIDs, []string{"en"}, false, 2)
+	if len(results) != len(videoIDs) {
+		t.Fatalf("Expected %d results, got %d", len(videoIDs), len(results))
+	}
+	for i, result := range results {
+		if result.VideoID != videoIDs[i] || result.Err != context.Canceled {
+			t.Errorf("Result %d: expected %s with context.Canceled, got %s with %v", i, videoIDs[i], result.VideoID, result.Err)
+		}
+	}
+
+	for _, result := range api.ListBatch(ctx, videoIDs, 2) {
+		if result.Err != context.Canceled {
+			t.Errorf("Expected context.Canceled for %s, got %v", result.VideoID, result.Err)
+		}
+	}
+
+	for _, result := range collectBatch(api.FetchStreamBatch(ctx, videoIDs, []string{"en"}, false, 2)) {
+		t.Errorf("Expected no streamed results after cancellation, got %s", result.VideoID)
+	}
+
+	waitForGoroutines(t, before)
+
+	// Cancel while the first requests are in flight: the server blocks until the request is aborted
+	var requests int32
+	arrived := make(chan struct{})
+	var once sync.Once
+	server, transport := newFakeYouTubeServer(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		once.Do(func() { close(arrived) })
+		<-r.Context().Done()
+	})
+	api, err = NewYouTubeTranscriptApi(nil)
+	if err != nil {
+		t.Fatalf("Failed to create API: %v", err)
+	}
+	// Install the fake transport as the instance's own transport (not WithSharedTransport), so Close owns its connections
+	api.fetcher.httpClient.transport = transport
+	videoIDs = []string{testVideoID, altTestVideoID, "another", "yet-another"}
+	ctx, cancel = context.WithCancel(context.Background())
+	go func() {
+		<-arrived
+		cancel()
+	}()
+	results = api.FetchBatch(ctx, videoIDs, []string{"en"}, false, 2)
+	for i, result := range results {
+		if result.VideoID != videoIDs[i] || result.Err != context.Canceled {
+			t.Errorf("Result %d: expected %s with context.Canceled, got %s with %v", i, videoIDs[i], result.VideoID, result.Err)
+		}
+	}
+	if got := atomic.LoadInt32(&requests); got > 2 {
+		t.Errorf("Expected no dispatch after cancellation, got %d requests", got)
+	}
+	api.Close()
+	server.Close()
+	waitForGoroutines(t, before)
+
+	// Completed requests leave the workers' keep-alive connections idle: Close alone must release them
+	server, transport = newFakeYouTubeServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer server.Close()
+	before = runtime.NumGoroutine()
+	api, err = NewYouTubeTranscriptApi(nil)
+	if err != nil {
+		t.Fatalf("Failed to create API: %v", err)
+	}
+	api.fetcher.httpClient.transport = transport
+	for _, result := range api.FetchBatch(context.Background(), videoIDs, []string{"en"}, false, 2) {
+		if result.Err == nil {
+			t.Errorf("Expected an error for %s from the fake server", result.VideoID)
+		}
+	}
+	api.Close()
+	waitForGoroutines(t, before)
+}
+
+// TestFetchBatch_CloseDuringBatch tests closing the instance while batch workers are using it (run with -race)
+func TestFetchBatch_CloseDuringBatch(t *testing.T) {
+	arrived := make(chan struct{}, 1)
+	server, transport := newFakeYouTubeServer(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case arrived <- struct{}{}:
+		default:
+		}
+		http.NotFound(w, r)
+	})
+	defer server.Close()
+
+	api, err := NewYouTubeTranscriptApi(nil)
+	if err != nil {
+		t.Fatalf("Failed to create API: %v", err)
+	}
+	api.fetcher.httpClient.transport = transport
+
+	// Close is not ordered with the workers' requests, so unsynchronized state shows up under -race
+	go func() {
+		<-arrived
+		api.Close()
+	}()
+	var videoIDs []string
+	for i := 0; i < 200; i++ {
+		videoIDs = append(videoIDs, fmt.Sprintf("video%03d", i))
+	}
+	closed := 0
+	for _, result := range api.FetchBatch(context.Background(), videoIDs, []string{"en"}, false, 4) {
+		if result.Err == nil {
+			t.Errorf("Expected an error for %s from the fake server", result.VideoID)
+		}
+		if result.Err == ErrClientClosed {
+			closed++
+		}
+	}
+	if closed == 0 {
+		t.Error("Expected videos started after Close to fail with ErrClientClosed")
+	}
+}
+
+// waitForGoroutines waits up to two seconds for the goroutine count to return to before
+func waitForGoroutines(t *testing.T, before int) {
+	deadline := time.Now().Add(2 * time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if after := runtime.NumGoroutine(); after > before {
+		t.Errorf("Goroutine leak: %d before, %d after", before, after)
+	}
+}
+
+func collectBatch(results <-chan BatchResult) []BatchResult {
+	var collected []BatchResult
+	for result := range results {
+		collected = append(collected, result)
+	}
+	return collected
+}
+
+// TestInnertubeClient_WithDetectedVersion tests extracting the client version from the watch page
+func TestInnertubeClient_WithDetectedVersion(t *testing.T) {
+	html := `ytcfg.set({"INNERTUBE_API_KEY":"key","INNERTUBE_CLIENT_NAME":"WEB","INNERTUBE_CLIENT_VERSION":"2.20260101.00.00"});`
+
+	if client := InnertubeClientWeb.withDetectedVersion(html); client.Version != "2.20260101.00.00" {
+		t.Errorf("Expected detected version, got %s", client.Version)
+	}
+	if client := InnertubeClientAndroid.withDetectedVersion(html); client.Version != InnertubeClientAndroid.Version {
+		t.Errorf("Expected the pinned Android version, got %s", client.Version)
+	}
+	if client := InnertubeClientWeb.withDetectedVersion("<html></html>"); client.Version != InnertubeClientWeb.Version {
+		t.Errorf("Expected fallback to the pinned version, got %s", client.Version)
+	}
+}
+
+// TestFetchedTranscript_DetectLanguage tests the heuristic content language detection
+func TestFetchedTranscript_DetectLanguage(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"So this is what we have to do with the rest of the day", "en"},
+		{"Pero lo que es muy importante es que la gente de aquí", "es"},
+		{"Ich weiß nicht, ob das auch für sie ist", "de"},
+		{"今日はいい天気ですね", "ja"},
+		{"我们今天讨论一下这个问题", "zh"},
+		{"안녕하세요 여러분", "ko"},
+		{"12345 !!!", ""},
+	}
+
+	for _, tt := range tests {
+		transcript := newTestFetchedTranscript()
+		transcript.Snippets = []FetchedTranscriptSnippet{{Text: tt.text, Start: 0, Duration: 1}}
+
+		code, confidence := transcript.DetectLanguage()
+		if code != tt.expected {
+			t.Errorf("DetectLanguage(%q) = %q, expected %q", tt.text, code, tt.expected)
+		}
+		if confidence < 0 || confidence > 1 || (code != "" && confidence == 0) {
+			t.Errorf("DetectLanguage(%q) returned invalid confidence %v", tt.text, confidence)
+		}
+	}
+}
+
+// TestFetchedTranscript_WithComputedDurations tests rewriting durations to the gap to the next snippet
+func TestFetchedTranscript_WithComputedDurations(t *testing.T) {
+	transcript := newTestFetchedTranscript()
+	transcript.Snippets[0].Duration = 3.0 // overlaps the next snippet
+
+	computed := transcript.WithComputedDurations()
+	if computed.Snippets[0].Duration != 1.5 {
+		t.Errorf("Expected first duration 1.5, got %v", computed.Snippets[0].Duration)
+	}
+	if computed.Snippets[1].Duration != 2 {
+		t.Errorf("Expected last duration to keep its original value, got %v", computed.Snippets[1].Duration)
+	}
+	if transcript.Snippets[0].Duration != 3.0 {
+		t.Error("WithComputedDurations should not modify the original transcript")
+	}
+}
+
+// TestNoTranscriptFound_TranslatableTo tests the translation hint on NoTranscriptFound
+func TestNoTranscriptFound_TranslatableTo(t *testing.T) {
+	_, err := newTestTranscriptList().FindTranscript([]string{"de", "ja", "fr"})
+	notFound, ok := err.(*NoTranscriptFound)
+	if !ok {
+		t.Fatalf("Expected NoTranscriptFound, got %T: %v", err, err)
+	}
+	if strings.Join(notFound.TranslatableTo, ",") != "de,fr" {
+		t.Errorf("Expected [de fr], got %v", notFound.TranslatableTo)
+	}
+	if !strings.Contains(err.Error(), "Transcript.Translate") || !strings.Contains(err.Error(), "[de fr]") {
+		t.Errorf("Expected the error message to mention Transcript.Translate for [de fr], got %q", err.Error())
+	}
+
+	if message := NewNoTranscriptFound(testVideoID, []string{"en"}, nil).Error(); !strings.Contains(message, "[en]") {
+		t.Errorf("Expected the requested languages in the message without transcript data, got %q", message)
+	}
+}
+
+// TestTranscript_FetchRefreshOnForbidden tests that a 403 on the caption URL is retried once with a refreshed URL
+func TestTranscript_FetchRefreshOnForbidden(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("sig") != "fresh" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		fmt.Fprint(w, testTranscriptXML)
+	}))
+	defer server.Close()
+
+	transcript := newTestTranscript(t, server.URL+"/api/timedtext?sig=stale")
+	_, err := transcript.Fetch(false)
+	if requestFailed, ok := err.(*YouTubeRequestFailed); !ok || requestFailed.StatusCode != http.StatusForbidden {
+		t.Fatalf("Expected YouTubeRequestFailed with status 403 without refresh, got %T: %v", err, err)
+	}
+
+	refreshes := 0
+	transcript.refreshURL = func(ctx context.Context) (string, error) {
+		refreshes++
+		return server.URL + "/api/timedtext?sig=fresh", nil
+	}
+	fetched, err := transcript.Fetch(false)
+	if err != nil {
+		t.Fatalf("Expected refreshed fetch to succeed, got %v", err)
+	}
+	if refreshes != 1 || len(fetched.Snippets) != 2 {
+		t.Errorf("Expected one refresh and 2 snippets, got %d refreshes and %d snippets", refreshes, len(fetched.Snippets))
+	}
+}
+
+// TestWithSharedTransport tests that instances share the transport but keep their own cookie jars
+func TestWithSharedTransport(t *testing.T) {
+	transport := &http.Transport{}
+	first, err := NewYouTubeTranscriptApi(nil, WithSharedTransport(transport))
+	if err != nil {
+		t.Fatalf("Failed to create API: %v", err)
+	}
+	second, err := NewYouTubeTranscriptApi(nil, WithSharedTransport(transport))
+	if err != nil {
+		t.Fatalf("Failed to create API: %v", err)
+	}
+
+	if first.fetcher.httpClient.getTransport() != transport || second.fetcher.httpClient.getTransport() != transport {
+		t.Error("Expected both instances to use the shared transport")
+	}
+	if first.fetcher.httpClient.Jar == second.fetcher.httpClient.Jar {
+		t.Error("Expected each instance to keep its own cookie jar")
+	}
+
+	first.fetcher.httpClient.resetTransport()
+	if first.fetcher.httpClient.getTransport() != transport {
+		t.Error("Expected resetTransport to leave the shared transport in place")
+	}
+}
+
+// TestWithSharedTransport_Conflicts tests that settings a shared transport would drop are rejected
+func TestWithSharedTransport_Conflicts(t *testing.T) {
+	transport := &http.Transport{}
+	proxyConfig, err := NewGenericProxyConfig("http://proxy.example.com:8080", "")
+	if err != nil {
+		t.Fatalf("Failed to create proxy config: %v", err)
+	}
+	pool, err := NewHashedProxyPool([]string{"http://proxy1.example.com:8080", "http://proxy2.example.com:8080"})
+	if err != nil {
+		t.Fatalf("Failed to create proxy pool: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		proxyConfig ProxyConfig
+		opts        []Option
+	}{
+		{"proxy config", proxyConfig, nil},
+		{"hashed proxy pool", pool, nil},
+		{"HTTP/1.1 only", nil, []Option{WithHTTP1Only()}},
+		{"dial timeout", nil, []Option{WithDialTimeout(time.Second)}},
+		{"TLS handshake timeout", nil, []Option{WithTLSHandshakeTimeout(time.Second)}},
+		{"response header timeout", nil, []Option{WithResponseHeaderTimeout(time.Second)}},
+		{"local address", nil, []Option{WithLocalAddr(&net.TCPAddr{IP: net.ParseIP("127.0.0.1")})}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option{WithSharedTransport(transport)}, tt.opts...)
+			if _, err := NewYouTubeTranscriptApi(tt.proxyConfig, opts...); !errors.Is(err, ErrSharedTransportConflict) {
+				t.Errorf("Expected ErrSharedTransportConflict, got %v", err)
+			}
+			if _, err := NewYouTubeTranscriptApi(tt.proxyConfig, tt.opts...); err != nil {
+				t.Errorf("Expected the setting to be accepted without a shared transport, got %v", err)
+			}
+		})
+	}
+
+	if _, err := NewYouTubeTranscriptApi(nil, WithSharedTransport(transport), WithRetries(2, time.Millisecond)); err != nil {
+		t.Errorf("Expected client-level settings to work with a shared transport, got %v", err)
+	}
+}
+
+// TestDiagnose tests the stage and error reported for each way of failing, against a fake server
+func TestDiagnose(t *testing.T) {
+	captchaPage := `<html><body><p>Our systems have detected unusual traffic from your computer network.</p></body></html>`
+	unavailable := `{"playabilityStatus":{"status":"ERROR","reason":"` + string(PlayabilityFailedReasonVideoUnavailable) + `"}}`
+	noCaptions := `{"playabilityStatus":{"status":"OK"},"videoDetails":{"title":"Test Video"}}`
+
+	tests := []struct {
+		name       string
+		watchPage  string
+		player     string
+		stage      DiagnosisStage
+		errType    string
+		blocked    bool
+		checkStage func(report DiagnosisReport) bool
+	}{
+		{"html blocked", captchaPage, testPlayerResponse, DiagnosisStageHTML, "*youtube_transcript_api.IpBlocked", true,
+			func(r DiagnosisReport) bool { return !r.HTMLFetched }},
+		{"api key missing", `<html><body>No config here</body></html>`, testPlayerResponse, DiagnosisStageAPIKey, "*youtube_transcript_api.YouTubeDataUnparsable", false,
+			func(r DiagnosisReport) bool { return r.HTMLFetched && !r.APIKeyExtracted }},
+		{"unplayable", testWatchPageHTML, unavailable, DiagnosisStagePlayability, "*youtube_transcript_api.VideoUnavailable", false,
+			func(r DiagnosisReport) bool { return r.InnertubeOK && !r.Playable }},
+		{"no captions", testWatchPageHTML, noCaptions, DiagnosisStageCaptions, "*youtube_transcript_api.TranscriptsDisabled", false,
+			func(r DiagnosisReport) bool { return r.Playable && !r.CaptionsPresent }},
+		{"success", testWatchPageHTML, testPlayerResponse, "", "<nil>", false,
+			func(r DiagnosisReport) bool {
+				return r.HTMLFetched && r.APIKeyExtracted && r.InnertubeOK && r.Playable && r.CaptionsPresent && r.ManualTrackCount == 1
+			}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, transport := newFakeYouTubeServer(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/watch":
+					fmt.Fprint(w, tt.watchPage)
+				case "/youtubei/v1/player":
+					fmt.Fprint(w, tt.player)
+				default:
+					http.NotFound(w, r)
+				}
+			})
+			defer server.Close()
+
+			api, err := NewYouTubeTranscriptApi(nil, WithSharedTransport(transport))
+			if err != nil {
+				t.Fatalf("Failed to create API: %v", err)
+			}
+			report, err := api.Diagnose(testVideoID)
+			if err != report.Err {
+				t.Errorf("Expected the returned error to match report.Err, got %v and %v", err, report.Err)
+			}
+			if report.FailedStage != tt.stage {
+				t.Errorf("Expected failed stage %q, got %q", tt.stage, report.FailedStage)
+			}
+			if errType := fmt.Sprintf("%T", report.Err); errType != tt.errType {
+				t.Errorf("Expected error %s, got %s: %v", tt.errType, errType, report.Err)
+			}
+			if report.Blocked() != tt.blocked {
+				t.Errorf("Expected Blocked() = %v, got %v", tt.blocked, report.Blocked())
+			}
+			if !tt.checkStage(report) {
+				t.Errorf("Unexpected stage flags: %+v", report)
+			}
+		})
+	}
+}
+
+// TestTextFormatterWithSeparators tests custom snippet and transcript separators
+func TestTextFormatterWithSeparators(t *testing.T) {
+	transcript := newTestFetchedTranscript()
+
+	output, err := NewTextFormatterWithSeparator(" ").FormatTranscripts([]*FetchedTranscript{transcript, transcript})
+	if err != nil {
+		t.Fatalf("Failed to format transcripts: %v", err)
+	}
+	if expected := "Hello there General Kenobi\n\n\nHello there General Kenobi"; output != expected {
+		t.Errorf("Expected %q, got %q", expected, output)
+	}
+
+	output, _ = NewTextFormatterWithSeparators("", "|").FormatTranscripts([]*FetchedTranscript{transcript, transcript})
+	if expected := "Hello thereGeneral Kenobi|Hello thereGeneral Kenobi"; output != expected {
+		t.Errorf("Expected %q, got %q", expected, output)
+	}
+}
+
+// TestFormatters_NilTranscript tests that every formatter rejects a nil transcript and skips nil entries
+func TestFormatters_NilTranscript(t *testing.T) {
+	formatters := map[string]Formatter{
+		"json":   &JSONFormatter{},
+		"pretty": &PrettyPrintFormatter{},
+		"text":   &TextFormatter{},
+		"srt":    NewSRTFormatter(),
+		"webvtt": NewWebVTTFormatter(),
+	}
+
+	for name, formatter := range formatters {
+		if _, err := formatter.FormatTranscript(nil); err != ErrNilTranscript {
+			t.Errorf("%s: expected ErrNilTranscript, got %v", name, err)
+		}
+
+		expected, err := formatter.FormatTranscripts([]*FetchedTranscript{newTestFetchedTranscript()})
+		if err != nil {
+			t.Fatalf("%s: failed to format transcripts: %v", name, err)
+		}
+		output, err := formatter.FormatTranscripts([]*FetchedTranscript{nil, newTestFetchedTranscript(), nil})
+		if err != nil {
+			t.Fatalf("%s: expected nil entries to be skipped, got %v", name, err)
+		}
+		if output != expected {
+			t.Errorf("%s: expected nil entries to be skipped, got %q", name, output)
+		}
+	}
+}
+
+// TestBuildTranscriptList_TracklistOnlyTranslations tests tracklist-level translation languages without translatable tracks
+func TestBuildTranscriptList_TracklistOnlyTranslations(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "caption_tracks_tracklist_translations.json"))
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+	var captionsJSON map[string]interface{}
+	if err := json.Unmarshal(data, &captionsJSON); err != nil {
+		t.Fatalf("Failed to decode fixture: %v", err)
+	}
+
+	transcriptList, err := BuildTranscriptList(nil, testVideoID, map[string]interface{}{"title": "Test Video"}, captionsJSON)
+	if err != nil {
+		t.Fatalf("Failed to build transcript list: %v", err)
+	}
+
+	if languages := transcriptList.TranslationLanguages(); len(languages) != 2 || languages[0].LanguageCode != "de" {
+		t.Errorf("Expected tracklist-level translation languages [de fr], got %v", languages)
+	}
+	if codes := transcriptList.AllTranslationLanguageCodes(); len(codes) != 0 {
+		t.Errorf("Expected no per-track translation targets, got %v", codes)
+	}
+
+	transcript, err := transcriptList.FindTranscript([]string{"en"})
+	if err != nil {
+		t.Fatalf("Expected an English track: %v", err)
+	}
+	if transcript.IsTranslatable() {
+		t.Error("Expected the track to follow its own isTranslatable flag")
+	}
+	if _, err := transcript.Translate("de"); err == nil {
+		t.Error("Expected translating a non-translatable track to fail")
+	} else if _, ok := err.(*NotTranslatable); !ok {
+		t.Errorf("Expected NotTranslatable, got %T: %v", err, err)
+	}
+}
+
+// TestYouTubeTranscriptApi_Reset tests that Reset clears cookies and the transport but keeps the configuration
+func TestYouTubeTranscriptApi_Reset(t *testing.T) {
+	api, err := NewYouTubeTranscriptApi(&GenericProxyConfig{HTTPURL: "http://proxy.example.com:8080"}, WithRetries(3, time.Millisecond))
+	if err != nil {
+		t.Fatalf("Failed to create API: %v", err)
+	}
+	httpClient := api.fetcher.httpClient
+
+	youtubeURL := &url.URL{Scheme: "https", Host: "www.youtube.com"}
+	httpClient.Jar.SetCookies(youtubeURL, []*http.Cookie{{Name: "CONSENT", Value: "bad", Path: "/"}})
+	transport := httpClient.getTransport()
+
+	if err := api.Reset(); err != nil {
+		t.Fatalf("Reset failed: %v", err)
+	}
+
+	if cookies := httpClient.Jar.Cookies(youtubeURL); len(cookies) != 0 {
+		t.Errorf("Expected cookies to be cleared, got %v", cookies)
+	}
+	if httpClient.getTransport() == transport {
+		t.Error("Expected the transport to be rebuilt")
+	}
+	if httpClient.HTTPProxy == nil || httpClient.MaxRetries != 3 || httpClient.Headers["Accept-Language"] != "en-US" {
+		t.Error("Expected proxy, retries and headers to be preserved")
+	}
+}
+
+// TestFetchedTranscript_Sentences tests merging snippets into sentences with abbreviation awareness
+func TestFetchedTranscript_Sentences(t *testing.T) {
+	transcript := newTestFetchedTranscript()
+	transcript.Snippets = []FetchedTranscriptSnippet{
+		{Text: "Hello there. This is", Start: 0, Duration: 2},
+		{Text: "Mr. Smith speaking", Start: 2, Duration: 2},
+		{Text: "to you! Did J. Doe", Start: 4, Duration: 2},
+		{Text: "call? I think so", Start: 6, Duration: 1.5},
+	}
+
+	expected := []Sentence{
+		{Text: "Hello there.", Start: 0, End: 2},
+		{Text: "This is Mr. Smith speaking to you!", Start: 0, End: 6},
+		{Text: "Did J. Doe call?", Start: 4, End: 7.5},
+		{Text: "I think so", Start: 6, End: 7.5},
+	}
+	sentences := transcript.Sentences()
+	if len(sentences) != len(expected) {
+		t.Fatalf("Expected %d sentences, got %d: %+v", len(expected), len(sentences), sentences)
+	}
+	for i, sentence := range sentences {
+		if sentence != expected[i] {
+			t.Errorf("Sentence %d: expected %+v, got %+v", i, expected[i], sentence)
+		}
+	}
+
+	// Without Latin sentence punctuation every snippet becomes a sentence
+	transcript.Snippets = []FetchedTranscriptSnippet{
+		{Text: "你好", Start: 0, Duration: 1},
+		{Text: "世界", Start: 1, Duration: 1},
+	}
+	if sentences := transcript.Sentences(); len(sentences) != 2 || sentences[1] != (Sentence{Text: "世界", Start: 1, End: 2}) {
+		t.Errorf("Expected snippet-based fallback, got %+v", sentences)
+	}
+}
+
+// TestCaptionsRequirePoToken tests detecting caption track lists that are unusable without a PO token
+func TestCaptionsRequirePoToken(t *testing.T) {
+	tracks := func(urls ...string) map[string]interface{} {
+		var captionTracks []interface{}
+		for _, u := range urls {
+			captionTracks = append(captionTracks, map[string]interface{}{"baseUrl": u})
+		}
+		return map[string]interface{}{"captionTracks": captionTracks}
+	}
+
+	if !captionsRequirePoToken(tracks("https://example.com/a?v=1&exp=xpe", "https://example.com/b?v=1&exp=xpe")) {
+		t.Error("Expected tracks that all need a PO token to be detected")
+	}
+	if captionsRequirePoToken(tracks("https://example.com/a?v=1&exp=xpe", "https://example.com/b?v=1")) {
+		t.Error("Expected a usable track to make the result usable")
+	}
+	if captionsRequirePoToken(tracks()) {
+		t.Error("Expected an empty track list not to be treated as requiring a PO token")
+	}
+}
+
+// TestCSVFormatter tests the default and timeline CSV column sets
+func TestCSVFormatter(t *testing.T) {
+	transcript := newTestFetchedTranscript()
+	// Overlapping durations: the first snippet ends when the second one starts
+	transcript.Snippets[0].Duration = 3
+
+	output, err := NewCSVFormatter().FormatTranscript(transcript)
+	if err != nil {
+		t.Fatalf("Failed to format transcript: %v", err)
+	}
+	if expected := "start,duration,text\n0,3,Hello there\n1.5,2,General Kenobi\n"; output != expected {
+		t.Errorf("Expected %q, got %q", expected, output)
+	}
+
+	formatter, err := NewCSVFormatterWithColumns(TimelineCSVColumns...)
+	if err != nil {
+		t.Fatalf("Failed to create formatter: %v", err)
+	}
+	output, err = formatter.FormatTranscripts([]*FetchedTranscript{transcript})
+	if err != nil {
+		t.Fatalf("Failed to format transcripts: %v", err)
+	}
+	expected := "video_id,index,start,end,duration,text\n" +
+		testVideoID + ",0,0,1.5,3,Hello there\n" +
+		testVideoID + ",1,1.5,3.5,2,General Kenobi\n"
+	if output != expected {
+		t.Errorf("Expected %q, got %q", expected, output)
+	}
+
+	if _, err := NewCSVFormatterWithColumns("start", "speaker"); err == nil {
+		t.Error("Expected an error for an unknown column")
+	}
+}
+
+// TestHTTPClient_ResponseHeaderTimeout tests that a server which never sends headers fails fast
+func TestHTTPClient_ResponseHeaderTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	api, err := NewYouTubeTranscriptApi(nil,
+		WithDialTimeout(time.Second), WithTLSHandshakeTimeout(time.Second), WithResponseHeaderTimeout(50*time.Millisecond))
+	if err != nil {
+		t.Fatalf("Failed to create API: %v", err)
+	}
+	httpClient := api.fetcher.httpClient
+	if transport := httpClient.getTransport(); transport.TLSHandshakeTimeout != time.Second || transport.DialContext == nil {
+		t.Error("Expected dial and TLS handshake timeouts to be applied to the transport")
+	}
+	if !httpClient.getTransport().ForceAttemptHTTP2 {
+		t.Error("Expected a custom dialer to keep HTTP/2 enabled")
+	}
+
+	http1, err := NewYouTubeTranscriptApi(nil, WithDialTimeout(time.Second), WithHTTP1Only())
+	if err != nil {
+		t.Fatalf("Failed to create API: %v", err)
+	}
+	if http1.fetcher.httpClient.getTransport().ForceAttemptHTTP2 {
+		t.Error("Expected WithHTTP1Only to disable HTTP/2 with a custom dialer")
+	}
+
+	start := time.Now()
+	if _, err := httpClient.Get(server.URL); err == nil {
+		t.Fatal("Expected a response header timeout error")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected the request to fail fast, took %v", elapsed)
+	}
+}
+
+// TestParseAcceptLanguage tests ordering by q-value and handling of wildcards and malformed segments
+func TestParseAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		header   string
+		expected []string
+	}{
+		{"de-CH, fr;q=0.9, en;q=0.8, de;q=0.7, *;q=0.5", []string{"de-CH", "fr", "en", "de"}},
+		{"en;q=0.5, ja", []string{"ja", "en"}},
+		{"pt-BR,es;q=0.8", []string{"pt-BR", "es", "pt"}},
+		{"fr;q=0, en;q=abc, ,;;, !!, zh_Hant", []string{"zh-Hant", "zh"}},
+		{"EN, en;q=0.9", []string{"EN"}},
+		{"", []string{}},
+	}
+
+	for _, tt := range tests {
+		if got := ParseAcceptLanguage(tt.header); strings.Join(got, ",") != strings.Join(tt.expected, ",") {
+			t.Errorf("ParseAcceptLanguage(%q) = %v, expected %v", tt.header, got, tt.expected)
+		}
+	}
+}
+
+// TestWithLocalAddr tests that the configured local address is set on the dialer
+func TestWithLocalAddr(t *testing.T) {
+	addr := &net.TCPAddr{IP: net.ParseIP("127.0.0.1")}
+	api, err := NewYouTubeTranscriptApi(nil, WithLocalAddr(addr))
+	if err != nil {
+		t.Fatalf("Failed to create API: %v", err)
+	}
+
+	dialer := api.fetcher.httpClient.dialer()
+	if dialer == nil || dialer.LocalAddr != addr {
+		t.Fatalf("Expected the dialer to use local address %v, got %+v", addr, dialer)
+	}
+	if transport := api.fetcher.httpClient.getTransport(); transport.DialContext == nil || !transport.ForceAttemptHTTP2 {
+		t.Error("Expected the transport to use the custom dialer with HTTP/2 enabled")
+	}
+
+	plain, err := NewYouTubeTranscriptApi(nil)
+	if err != nil {
+		t.Fatalf("Failed to create API: %v", err)
+	}
+	if plain.fetcher.httpClient.dialer() != nil {
+		t.Error("Expected the default dialer without WithLocalAddr or WithDialTimeout")
+	}
+}
+
+// TestTranscriptList_FindTranscriptFuzzy tests the match kind reported for each fuzzy match level
+func TestTranscriptList_FindTranscriptFuzzy(t *testing.T) {
+	transcriptList := newTestTranscriptList()
+	transcriptList.manuallyCreatedTranscripts["pt-BR"] = NewTranscript(nil, testVideoID, "Test Video", "", "https://example.com/pt-BR", "Portuguese (Brazil)", "pt-BR", false, nil)
+
+	tests := []struct {
+		languages    []string
+		expectedCode string
+		expectedKind MatchKind
+	}{
+		{[]string{"EN"}, "en", MatchExact},
+		{[]string{"en-US"}, "en", MatchBaseLanguage},
+		{[]string{"ja", "es_MX"}, "es", MatchBaseLanguage},
+		{[]string{"pt"}, "pt-BR", MatchRegionFallback},
+		{[]string{"pt-PT"}, "pt-BR", MatchRegionFallback},
+		{[]string{"de"}, "de", MatchTranslation},
+	}
+
+	for _, tt := range tests {
+		match, err := transcriptList.FindTranscriptFuzzy(tt.languages)
+		if err != nil {
+			t.Errorf("FindTranscriptFuzzy(%v) failed: %v", tt.languages, err)
+			continue
+		}
+		if match.Transcript.LanguageCode != tt.expectedCode || match.Kind != tt.expectedKind {
+			t.Errorf("FindTranscriptFuzzy(%v) = %s (%s), expected %s (%s)",
+				tt.languages, match.Transcript.LanguageCode, match.Kind, tt.expectedCode, tt.expectedKind)
+		}
+		if match.Exact() != (tt.expectedKind == MatchExact) {
+			t.Errorf("FindTranscriptFuzzy(%v).Exact() = %v", tt.languages, match.Exact())
+		}
+	}
+
+	if _, err := transcriptList.FindTranscriptFuzzy([]string{"ja"}); err == nil {
+		t.Error("Expected NoTranscriptFound for an unavailable language")
+	} else if _, ok := err.(*NoTranscriptFound); !ok {
+		t.Errorf("Expected NoTranscriptFound, got %T: %v", err, err)
+	}
+}
+
+// TestTranscript_DisplayLabel tests labels for manual, generated and translated transcripts
+func TestTranscript_DisplayLabel(t *testing.T) {
+	transcriptList := newTestTranscriptList()
+	manual := transcriptList.manuallyCreatedTranscripts["en"]
+	generated := transcriptList.generatedTranscripts["en"]
+
+	if label := manual.DisplayLabel(); label != "English" {
+		t.Errorf("Expected \"English\", got %q", label)
+	}
+	if label := generated.DisplayLabel(); label != "English (auto-generated)" {
+		t.Errorf("Expected \"English (auto-generated)\", got %q", label)
+	}
+
+	translated, err := generated.Translate("de")
+	if err != nil {
+		t.Fatalf("Failed to translate: %v", err)
+	}
+	if translated.SourceLanguageCode != "en" {
+		t.Errorf("Expected source language en, got %q", translated.SourceLanguageCode)
+	}
+	if label := translated.DisplayLabel(); label != "German (auto-generated, translated from English)" {
+		t.Errorf("Unexpected translated label %q", label)
+	}
+
+	fetched := &FetchedTranscript{Language: "Klingon", LanguageCode: "tlh-x", IsGenerated: true}
+	if label := fetched.DisplayLabel(); label != "Klingon (auto-generated)" {
+		t.Errorf("Expected the YouTube name for unknown codes, got %q", label)
+	}
+}
+
+// TestFetchedTranscript_DuplicateStarts tests deterministic tie-breaking for snippets sharing a start time
+func TestFetchedTranscript_DuplicateStarts(t *testing.T) {
+	transcript := newTestFetchedTranscript()
+	transcript.Snippets = []FetchedTranscriptSnippet{
+		{Text: "later", Start: 2, Duration: 1},
+		{Text: "first", Start: 0, Duration: 1},
+		{Text: "second", Start: 0, Duration: 1.5},
+		{Text: "third", Start: 0, Duration: 0.5},
+	}
+
+	sorted := transcript.SortByStart()
+	var texts []string
+	for _, snippet := range sorted.Snippets {
+		texts = append(texts, snippet.Text)
+	}
+	if strings.Join(texts, ",") != "first,second,third,later" {
+		t.Fatalf("Expected a stable sort by start, got %v", texts)
+	}
+	if transcript.Snippets[0].Text != "later" {
+		t.Error("SortByStart should not modify the original transcript")
+	}
+
+	tests := []struct {
+		at       float64
+		expected int
+		found    bool
+	}{
+		{0, 0, true},
+		{0.9, 0, true},
+		{1.2, 1, true}, // the first snippet has ended, the second still covers 1.2
+		{1.7, 0, false},
+		{2.5, 3, true},
+		{-1, 0, false},
+	}
+	for _, tt := range tests {
+		index, found := sorted.SnippetAt(tt.at)
+		if index != tt.expected || found != tt.found {
+			t.Errorf("SnippetAt(%v) = (%d, %v), expected (%d, %v)", tt.at, index, found, tt.expected, tt.found)
+		}
+	}
+
+	if sliced := sorted.Slice(0, 1); len(sliced.Snippets) != 3 || sliced.Snippets[1].Text != "second" {
+		t.Errorf("Expected Slice to keep duplicate-start snippets in order, got %+v", sliced.Snippets)
+	}
+
+	output, err := NewSRTFormatter().FormatTranscript(sorted)
+	if err != nil {
+		t.Fatalf("Failed to format transcript: %v", err)
+	}
+	if strings.Contains(output, "00:00:00,000 --> 00:00:00,000") {
+		t.Errorf("Duplicate starts should not produce zero-length cues:\n%s", output)
+	}
+	if !strings.Contains(output, "1\n00:00:00,000 --> 00:00:01,000\nfirst") {
+		t.Errorf("Expected the first duplicate to keep its own end time:\n%s", output)
+	}
+}
+
+// TestFetchedTranscript_Chapter tests parsing chapters from a description and slicing by chapter name
+func TestFetchedTranscript_Chapter(t *testing.T) {
+	description := "My video\n\nChapters:\n0:00 Intro\n0:01.5 not a chapter\n1:30 - Results\n(2:45) Outro\n\nhttps://example.com"
+	chapters := ParseChapters(description, 200)
+	expected := []Chapter{
+		{Title: "Intro", Start: 0, End: 90},
+		{Title: "Results", Start: 90, End: 165},
+		{Title: "Outro", Start: 165, End: 200},
+	}
+	if len(chapters) != len(expected) {
+		t.Fatalf("Expected %d chapters, got %+v", len(expected), chapters)
+	}
+	for i, chapter := range chapters {
+		if chapter != expected[i] {
+			t.Errorf("Chapter %d: expected %+v, got %+v", i, expected[i], chapter)
+		}
+	}
+	if ParseChapters("1:00 Intro\n2:00 Middle\n3:00 End", 0) != nil {
+		t.Error("Expected no chapters when the first timestamp is not 0:00")
+	}
+
+	transcript := newTestFetchedTranscript()
+	transcript.Snippets = []FetchedTranscriptSnippet{
+		{Text: "welcome", Start: 0, Duration: 5},
+		{Text: "the numbers", Start: 95, Duration: 5},
+		{Text: "bye", Start: 170, Duration: 5},
+	}
+	if _, ok := transcript.Chapter("Results"); ok {
+		t.Error("Expected false without chapters")
+	}
+
+	transcript.Chapters = chapters
+	results, ok := transcript.Chapter("results")
+	if !ok || len(results.Snippets) != 1 || results.Snippets[0].Text != "the numbers" {
+		t.Errorf("Expected the Results chapter snippets, got %+v (ok=%v)", results, ok)
+	}
+	if _, ok := transcript.Chapter("Appendix"); ok {
+		t.Error("Expected false for an unknown chapter")
+	}
+
+	transcriptList, err := BuildTranscriptList(nil, testVideoID,
+		map[string]interface{}{"title": "Test Video", "shortDescription": description, "lengthSeconds": "200"},
+		map[string]interface{}{"captionTracks": []interface{}{
+			map[string]interface{}{"baseUrl": "https://example.com/en", "languageCode": "en"},
+		}})
+	if err != nil {
+		t.Fatalf("Failed to build transcript list: %v", err)
+	}
+	if found, err := transcriptList.FindTranscript([]string{"en"}); err != nil || len(found.chapters) != 3 {
+		t.Errorf("Expected chapters to be attached to the transcript, got %v", err)
+	}
+}
+
+// TestRetryBudgetStopsRetrying tests that retries stop once the retry budget is exhausted
+func TestRetryBudgetStopsRetrying(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	api, err := NewYouTubeTranscriptApi(nil, WithRetries(100, 20*time.Millisecond), WithRetryBudget(100*time.Millisecond))
+	if err != nil {
+		t.Fatalf("Failed to create API: %v", err)
+	}
+
+	start := time.Now()
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	resp, err := api.fetcher.httpClient.do(req)
+	elapsed := time.Since(start)
+	if err != nil {
+		t.Fatalf("Expected the last response to be returned, got error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Errorf("Expected status 500, got %d", resp.StatusCode)
+	}
+	if elapsed > time.Second {
+		t.Errorf("Expected retries to stop within the budget, took %v", elapsed)
+	}
+	if requests < 2 || requests > 4 {
+		t.Errorf("Expected 2-4 requests within the budget, got %d", requests)
+	}
+}
+
+// TestRetryBackoffCap tests that the exponential backoff is capped by MaxRetryBackoff
+func TestRetryBackoffCap(t *testing.T) {
+	client := &HTTPClient{RetryBackoff: time.Second, MaxRetryBackoff: 5 * time.Second}
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for attempt, want := range expected {
+		if got := client.retryBackoff(attempt); got != want {
+			t.Errorf("Attempt %d: expected backoff %v, got %v", attempt, want, got)
+		}
+	}
+}
+
+// TestFetchFormattedRejectsUnknownFormat tests that FetchFormatted validates the format name
+func TestFetchFormattedRejectsUnknownFormat(t *testing.T) {
+	api, err := NewYouTubeTranscriptApi(nil)
+	if err != nil {
+		t.Fatalf("Failed to create API: %v", err)
+	}
+
+	// The format is validated before any request, so no network is needed
+	_, err = api.FetchFormatted(testVideoID, []string{"en"}, false, "yaml")
+	if err == nil {
+		t.Fatal("Expected error for unknown format")
+	}
+	if !strings.Contains(err.Error(), "'yaml' is not supported") {
+		t.Errorf("Expected unsupported format error, got: %v", err)
+	}
+}
+
+// TestFetchFormatted tests fetching and formatting a transcript in one call
+func TestFetchFormatted(t *testing.T) {
+	server, transport := newFakeYouTubeServer(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/watch":
+			fmt.Fprint(w, testWatchPageHTML)
+		case "/youtubei/v1/player":
+			fmt.Fprint(w, testPlayerResponse)
+		case "/api/timedtext":
+			fmt.Fprint(w, testTranscriptXML)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	defer server.Close()
+
+	api, err := NewYouTubeTranscriptApi(nil, WithSharedTransport(transport))
+	if err != nil {
+		t.Fatalf("Failed to create API: %v", err)
+	}
+
+	output, err := api.FetchFormatted(testVideoID, []string{"en"}, false, "srt")
+	if err != nil {
+		t.Fatalf("Failed to fetch formatted transcript: %v", err)
+	}
+	if !strings.HasPrefix(output, "1\n00:00:") || !strings.Contains(output, "General Kenobi") {
+		t.Errorf("Expected SRT output, got: %.80s", output)
+	}
+}
+
+// TestErrorCodes tests the machine-readable code of every error type
+func TestErrorCodes(t *testing.T) {
+	tests := []struct {
+		err  error
+		code string
+	}{
+		{NewYouTubeDataUnparsable(testVideoID), "YOUTUBE_DATA_UNPARSABLE"},
+		{NewYouTubeRequestFailed(testVideoID, fmt.Errorf("boom")), "YOUTUBE_REQUEST_FAILED"},
+		{NewVideoUnplayable(testVideoID, "", nil), "VIDEO_UNPLAYABLE"},
+		{NewVideoUnavailable(testVideoID), "VIDEO_UNAVAILABLE"},
+		{NewInvalidVideoId(testVideoID), "INVALID_VIDEO_ID"},
+		{NewRequestBlocked(testVideoID), "REQUEST_BLOCKED"},
+		{NewIpBlocked(testVideoID), "IP_BLOCKED"},
+		{NewTranscriptsDisabled(testVideoID), "TRANSCRIPTS_DISABLED"},
+		{NewAgeRestricted(testVideoID), "AGE_RESTRICTED"},
+		{NewNotTranslatable(testVideoID), "NOT_TRANSLATABLE"},
+		{NewTranslationLanguageNotAvailable(testVideoID), "TRANSLATION_LANGUAGE_NOT_AVAILABLE"},
+		{NewFailedToCreateConsentCookie(testVideoID), "FAILED_TO_CREATE_CONSENT_COOKIE"},
+		{NewNoTranscriptFound(testVideoID, []string{"en"}, nil), "NO_TRANSCRIPT_FOUND"},
+		{NewPoTokenRequired(testVideoID), "PO_TOKEN_REQUIRED"},
+		{NewPlaylistUnavailable("PLtest", ""), "PLAYLIST_UNAVAILABLE"},
+		{NewPlaylistRequestFailed("PLtest", fmt.Errorf("boom")), "PLAYLIST_REQUEST_FAILED"},
+		{NewPlaylistTruncated("PLtest", 3), "PLAYLIST_TRUNCATED"},
+		{NewCookiePathInvalid("cookies.txt"), "COOKIE_PATH_INVALID"},
+		{NewCookieInvalid("cookies.txt"), "COOKIE_INVALID"},
+		{&CookieError{YouTubeTranscriptApiException: &YouTubeTranscriptApiException{Message: "cookies"}}, "COOKIE_ERROR"},
+		{&YouTubeTranscriptApiException{Message: "base"}, "YOUTUBE_TRANSCRIPT_API_ERROR"},
+		{NewLimitExceeded(testVideoID, "bytes"), "LIMIT_EXCEEDED"},
+		{fmt.Errorf("wrapped: %w", NewTranscriptsDisabled(testVideoID)), "TRANSCRIPTS_DISABLED"},
+		{fmt.Errorf("plain"), "UNKNOWN"},
+		{nil, ""},
+	}
+
+	for _, tt := range tests {
+		if code := ErrorCode(tt.err); code != tt.code {
+			t.Errorf("ErrorCode(%T) = %q, expected %q", tt.err, code, tt.code)
+		}
+	}
+}
+
+// TestMergeParallel tests aligning two transcripts by overlapping time windows
+func TestMergeParallel(t *testing.T) {
+	a := &FetchedTranscript{Snippets: []FetchedTranscriptSnippet{
+		{Text: "Hello there", Start: 0, Duration: 2},
+		{Text: "General Kenobi", Start: 2, Duration: 2},
+		{Text: "You are a bold one", Start: 10, Duration: 2},
+	}}
+	b := &FetchedTranscript{Snippets: []FetchedTranscriptSnippet{
+		{Text: "Hallo", Start: 0, Duration: 1},
+		{Text: "du", Start: 1, Duration: 1.2},
+		{Text: "General Kenobi", Start: 2.2, Duration: 2},
+		{Text: "Musik", Start: 6, Duration: 1},
+	}}
+
+	pairs, err := MergeParallel(a, b)
+	if err != nil {
+		t.Fatalf("MergeParallel failed: %v", err)
+	}
+
+	expected := []ParallelSnippet{
+		{Start: 0, End: 2.2, TextA: "Hello there", TextB: "Hallo du"},
+		{Start: 2, End: 4.2, TextA: "General Kenobi", TextB: "General Kenobi"},
+		{Start: 6, End: 7, TextB: "Musik"},
+		{Start: 10, End: 12, TextA: "You are a bold one"},
+	}
+	if len(pairs) != len(expected) {
+		t.Fatalf("Expected %d pairs, got %d: %+v", len(expected), len(pairs), pairs)
+	}
+	for i, pair := range pairs {
+		if pair != expected[i] {
+			t.Errorf("Pair %d: expected %+v, got %+v", i, expected[i], pair)
+		}
+	}
+
+	if _, err := MergeParallel(a, nil); err != ErrNilTranscript {
+		t.Errorf("Expected ErrNilTranscript, got %v", err)
+	}
+}
+
+// TestParsePlaylistPage tests extracting video IDs and continuation tokens from playlist data
+func TestParsePlaylistPage(t *testing.T) {
+	html, err := os.ReadFile(filepath.Join("testdata", "playlist_initial_data.html"))
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+
+	data, err := extractYtInitialData(string(html), "PLtest")
+	if err != nil {
+		t.Fatalf("Failed to extract ytInitialData: %v", err)
+	}
+	page := parsePlaylistPage(data)
+
+	if !page.hasVideoList {
+		t.Error("Expected the page to contain a video list")
+	}
+	// The private entry is skipped; duplicates are removed by listPlaylistVideoIDs, not here
+	expected := []string{"GJLlxj_dtq8", "jNQXAC9IVRw", "GJLlxj_dtq8"}
+	if strings.Join(page.videoIDs, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected video IDs %v, got %v", expected, page.videoIDs)
+	}
+	if page.continuation != "NEXT_PAGE_TOKEN" {
+		t.Errorf("Expected continuation token NEXT_PAGE_TOKEN, got %q", page.continuation)
+	}
+
+	// A continuation response appends items and has no further token
+	var continuation map[string]interface{}
+	json.Unmarshal([]byte(`{"onResponseReceivedActions":[{"appendContinuationItemsAction":{"continuationItems":[
+		{"playlistVideoRenderer":{"videoId":"dQw4w9WgXcQ","isPlayable":true}}]}}]}`), &continuation)
+	page = parsePlaylistPage(continuation)
+	if len(page.videoIDs) != 1 || page.videoIDs[0] != "dQw4w9WgXcQ" || page.continuation != "" {
+		t.Errorf("Unexpected continuation page: %+v", page)
+	}
+
+	// A missing playlist only has an alert
+	var missing map[string]interface{}
+	json.Unmarshal([]byte(`{"alerts":[{"alertRenderer":{"type":"ERROR","text":{"runs":[{"text":"The playlist does not exist."}]}}}]}`), &missing)
+	page = parsePlaylistPage(missing)
+	if page.hasVideoList || page.alert != "The playlist does not exist." {
+		t.Errorf("Expected alert without video list, got %+v", page)
+	}
+}
+
+// TestPlaylistIDFromURL tests extracting the playlist ID from playlist and watch URLs
+func TestPlaylistIDFromURL(t *testing.T) {
+	tests := map[string]string{
+		"PLabc123": "PLabc123",
+		"https://www.youtube.com/playlist?list=PLabc123":                    "PLabc123",
+		"https://www.youtube.com/watch?v=GJLlxj_dtq8&list=PLabc123&index=2": "PLabc123",
+	}
+	for input, expected := range tests {
+		if got := playlistIDFromURL(input); got != expected {
+			t.Errorf("playlistIDFromURL(%q) = %q, expected %q", input, got, expected)
+		}
+	}
+}
+
+// TestListPlaylistVideoIDs tests listing the videos of a playlist
+func TestListPlaylistVideoIDs(t *testing.T) {
+	server, transport := newFakeYouTubeServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/playlist" || r.URL.Query().Get("list") != "PLthis-playlist-does-not-exist" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `<html><script>var ytInitialData = {"alerts":[{"alertRenderer":{"type":"ERROR",`+
+			`"text":{"runs":[{"text":"The playlist does not exist."}]}}}]};</script></html>`)
+	})
+	defer server.Close()
+
+	api, err := NewYouTubeTranscriptApi(nil, WithSharedTransport(transport))
+	if err != nil {
+		t.Fatalf("Failed to create API: %v", err)
+	}
+
+	_, err = api.ListPlaylistVideoIDs("PLthis-playlist-does-not-exist")
+	unavailable, ok := err.(*PlaylistUnavailable)
+	if !ok {
+		t.Fatalf("Expected PlaylistUnavailable for a missing playlist, got %T: %v", err, err)
+	}
+	if unavailable.Reason != "The playlist does not exist." {
+		t.Errorf("Expected the page alert as the reason, got %q", unavailable.Reason)
+	}
+}
+
+// TestListPlaylistVideoIDs_Pagination tests following continuation tokens through the browse endpoint
+func TestListPlaylistVideoIDs_Pagination(t *testing.T) {
+	html, err := os.ReadFile(filepath.Join("testdata", "playlist_initial_data.html"))
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+	browsePage := func(videoIDs []string, token string) string {
+		var items []string
+		for _, videoID := range videoIDs {
+			items = append(items, fmt.Sprintf(`{"playlistVideoRenderer":{"videoId":%q,"isPlayable":true}}`, videoID))
+		}
+		if token != "" {
+			items = append(items, fmt.Sprintf(`{"continuationItemRenderer":{"continuationEndpoint":{"continuationCommand":{"token":%q}}}}`, token))
+		}
+		return `{"onResponseReceivedActions":[{"appendContinuationItemsAction":{"continuationItems":[` + strings.Join(items, ",") + `]}}]}`
+	}
+
+	// The fixture page links to NEXT_PAGE_TOKEN; the last browse page links back to it
+	var browseTokens []string
+	pages := map[string]string{
+		"NEXT_PAGE_TOKEN":  browsePage([]string{"dQw4w9WgXcQ", "jNQXAC9IVRw"}, "THIRD_PAGE_TOKEN"),
+		"THIRD_PAGE_TOKEN": browsePage([]string{"9bZkp7q19f0"}, "NEXT_PAGE_TOKEN"),
+	}
+	endless := false
+	server, transport := newFakeYouTubeServer(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/playlist":
+			w.Write(html)
+		case "/youtubei/v1/browse":
+			var body struct {
+				Continuation string `json:"continuation"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			if r.Method != http.MethodPost || r.URL.Query().Get("key") != "test-key" {
+				t.Errorf("Unexpected browse request: %s %s", r.Method, r.URL)
+			}
+			browseTokens = append(browseTokens, body.Continuation)
+			if endless {
+				fmt.Fprint(w, browsePage([]string{fmt.Sprintf("video%d", len(browseTokens))}, fmt.Sprintf("TOKEN_%d", len(browseTokens))))
+				return
+			}
+			fmt.Fprint(w, pages[body.Continuation])
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	defer server.Close()
+
+	api, err := NewYouTubeTranscriptApi(nil, WithSharedTransport(transport))
+	if err != nil {
+		t.Fatalf("Failed to create API: %v", err)
+	}
+
+	videoIDs, err := api.ListPlaylistVideoIDs("https://www.youtube.com/playlist?list=PLtest")
+	if err != nil {
+		t.Fatalf("Failed to list playlist: %v", err)
+	}
+	expected := []string{"GJLlxj_dtq8", "jNQXAC9IVRw", "dQw4w9WgXcQ", "9bZkp7q19f0"}
+	if strings.Join(videoIDs, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected deduplicated video IDs %v, got %v", expected, videoIDs)
+	}
+	if strings.Join(browseTokens, ",") != "NEXT_PAGE_TOKEN,THIRD_PAGE_TOKEN" {
+		t.Errorf("Expected to stop at the repeated token, got browse requests for %v", browseTokens)
+	}
+
+	// A playlist that never runs out of pages stops at maxPlaylistPages and reports the truncation
+	browseTokens = nil
+	endless = true
+	videoIDs, err = api.ListPlaylistVideoIDs("PLtest")
+	truncated, ok := err.(*PlaylistTruncated)
+	if !ok {
+		t.Fatalf("Expected PlaylistTruncated, got %T: %v", err, err)
+	}
+	if len(browseTokens) != maxPlaylistPages-1 {
+		t.Errorf("Expected %d browse requests, got %d", maxPlaylistPages-1, len(browseTokens))
+	}
+	if truncated.VideoCount != len(videoIDs) || len(videoIDs) != 2+maxPlaylistPages-1 {
+		t.Errorf("Expected the retrieved video IDs alongside the error, got %d (error reports %d)", len(videoIDs), truncated.VideoCount)
+	}
+}
+
+// TestPlaylistErrors tests that playlist errors name the playlist URL instead of a watch URL
+func TestPlaylistErrors(t *testing.T) {
+	unavailable := NewPlaylistUnavailable("PLtest", "The playlist does not exist.").Error()
+	if !strings.Contains(unavailable, "https://www.youtube.com/playlist?list=PLtest") ||
+		!strings.Contains(unavailable, "The playlist does not exist.") || strings.Contains(unavailable, "watch?v=") {
+		t.Errorf("Unexpected PlaylistUnavailable message: %q", unavailable)
+	}
+
+	requestFailed := NewYouTubeRequestFailed("PLtest", fmt.Errorf("HTTP 500: 500 Internal Server Error"))
+	requestFailed.StatusCode = http.StatusInternalServerError
+	converted, ok := asPlaylistError(requestFailed).(*PlaylistRequestFailed)
+	if !ok {
+		t.Fatalf("Expected PlaylistRequestFailed, got %T", asPlaylistError(requestFailed))
+	}
+	if converted.StatusCode != http.StatusInternalServerError || !strings.Contains(converted.Error(), "playlist?list=PLtest") ||
+		!strings.Contains(converted.Error(), "HTTP 500") {
+		t.Errorf("Unexpected PlaylistRequestFailed: %+v (%q)", converted, converted.Error())
+	}
+
+	// Errors other than request failures keep their type
+	if _, ok := asPlaylistError(NewIpBlocked("PLtest")).(*IpBlocked); !ok {
+		t.Error("Expected IpBlocked to be returned unchanged")
+	}
+}
+
+// TestWithAbsoluteTimes tests adding the stream start time to snippet offsets
+func TestWithAbsoluteTimes(t *testing.T) {
+	var playerResponse map[string]interface{}
+	json.Unmarshal([]byte(`{"microformat":{"playerMicroformatRenderer":{"liveBroadcastDetails":{
+		"isLiveNow":false,"startTimestamp":"2024-03-01T17:00:35+00:00"}}}}`), &playerResponse)
+
+	streamStart, ok := StreamStartTime(playerResponse)
+	if !ok {
+		t.Fatal("Expected a stream start time")
+	}
+
+	snippets := newTestFetchedTranscript().WithAbsoluteTimes(streamStart)
+	expected := []time.Time{
+		time.Date(2024, 3, 1, 17, 0, 35, 0, time.UTC),
+		time.Date(2024, 3, 1, 17, 0, 36, int(500*time.Millisecond), time.UTC),
+	}
+	if len(snippets) != len(expected) {
+		t.Fatalf("Expected %d snippets, got %d", len(expected), len(snippets))
+	}
+	for i, at := range expected {
+		if !snippets[i].At.Equal(at) {
+			t.Errorf("Snippet %d: expected %v, got %v", i, at, snippets[i].At)
+		}
+	}
+	if snippets[1].Text != "General Kenobi" {
+		t.Errorf("Expected the snippet data to be kept, got %+v", snippets[1])
+	}
+
+	if _, ok := StreamStartTime(map[string]interface{}{}); ok {
+		t.Error("Expected no stream start time for a regular video")
+	}
+}
+
+// TestTranscriptContentParser tests plugging in a custom parser for the caption response body
+func TestTranscriptContentParser(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"events":[{"tStartMs":0,"dDurationMs":1500,"segs":[{"utf8":"Hello there"}]}]}`)
+	}))
+	defer server.Close()
+
+	var gotPreserveFormatting bool
+	json3Parser := TranscriptContentParserFunc(func(raw []byte, preserveFormatting bool) ([]FetchedTranscriptSnippet, error) {
+		gotPreserveFormatting = preserveFormatting
+		var data struct {
+			Events []struct {
+				TStartMs    float64 `json:"tStartMs"`
+				DDurationMs float64 `json:"dDurationMs"`
+				Segs        []struct {
+					UTF8 string `json:"utf8"`
+				} `json:"segs"`
+			} `json:"events"`
+		}
+		if err := json.Unmarshal(raw, &data); err != nil {
+			return DefaultTranscriptContentParser.Parse(raw, preserveFormatting)
+		}
+		var snippets []FetchedTranscriptSnippet
+		for _, event := range data.Events {
+			var text strings.Builder
+			for _, seg := range event.Segs {
+				text.WriteString(seg.UTF8)
+			}
+			snippets = append(snippets, FetchedTranscriptSnippet{Text: text.String(), Start: event.TStartMs / 1000, Duration: event.DDurationMs / 1000})
+		}
+		return snippets, nil
+	})
+
+	transcript := newTestTranscript(t, server.URL+"/api/timedtext?v="+testVideoID+"&fmt=json3")
+	transcript.captionOptions = newAPIOptions([]Option{WithTranscriptContentParser(json3Parser)}).captionOptions()
+	fetched, err := transcript.Fetch(true)
+	if err != nil {
+		t.Fatalf("Failed to fetch transcript: %v", err)
+	}
+	if len(fetched.Snippets) != 1 || fetched.Snippets[0] != (FetchedTranscriptSnippet{Text: "Hello there", Start: 0, Duration: 1.5}) {
+		t.Errorf("Unexpected snippets: %+v", fetched.Snippets)
+	}
+	if !gotPreserveFormatting {
+		t.Error("Expected preserveFormatting to be passed to the parser")
+	}
+
+	// The built-in parser satisfies the interface through DefaultTranscriptContentParser
+	snippets, err := DefaultTranscriptContentParser.Parse([]byte(testTranscriptXML), false)
+	if err != nil || len(snippets) == 0 {
+		t.Errorf("Expected the default parser to parse XML, got %d snippets, err %v", len(snippets), err)
+	}
+
+	// WithMinSnippetDuration also applies to a custom parser
+	transcript.captionOptions = newAPIOptions([]Option{WithTranscriptContentParser(json3Parser), WithMinSnippetDuration(2)}).captionOptions()
+	fetched, err = transcript.Fetch(false)
+	if err != nil {
+		t.Fatalf("Failed to fetch transcript: %v", err)
+	}
+	if len(fetched.Snippets) != 0 {
+		t.Errorf("Expected the short snippet to be dropped after the custom parser, got %+v", fetched.Snippets)
+	}
+}
+
+// TestTranscriptParser_ContentParser tests that a configured TranscriptParser can be used through Fetch
+func TestTranscriptParser_ContentParser(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<?xml version="1.0" encoding="utf-8" ?><transcript>`+
+			`<text start="0.0" dur="1.5">Tom &amp;amp; Jerry {ALIGN:START}</text>`+
+			`<text start="1.5" dur="2.0">&lt;b&gt;Bold&lt;/b&gt; move</text>`+
+			`</transcript>`)
+	}))
+	defer server.Close()
+
+	parser := NewTranscriptParser(false).WithDecodeEntities(false).ContentParser()
+	transcript := newTestTranscript(t, server.URL+"/api/timedtext?v="+testVideoID)
+	transcript.captionOptions = newAPIOptions([]Option{WithTranscriptContentParser(parser)}).captionOptions()
+
+	fetched, err := transcript.Fetch(false)
+	if err != nil {
+		t.Fatalf("Failed to fetch transcript: %v", err)
+	}
+	if len(fetched.Snippets) != 2 || fetched.Snippets[0].Text != "Tom &amp; Jerry" {
+		t.Errorf("Expected entities to be kept and cue tokens stripped, got %+v", fetched.Snippets)
+	}
+
+	// preserveFormatting comes from Fetch, not from the parser the adapter was built from
+	fetched, err = transcript.Fetch(true)
+	if err != nil {
+		t.Fatalf("Failed to fetch transcript: %v", err)
+	}
+	if len(fetched.Snippets) != 2 || fetched.Snippets[0].Text != "Tom &amp; Jerry {ALIGN:START}" || fetched.Snippets[1].Text != "<b>Bold</b> move" {
+		t.Errorf("Expected formatting to be preserved, got %+v", fetched.Snippets)
+	}
+}
+
+// TestTranscript_BindCaptionOptions tests that Bind switches to the caption settings of the bound instance
+func TestTranscript_BindCaptionOptions(t *testing.T) {
+	cache := NewMemoryCaptionCache()
+	api, err := NewYouTubeTranscriptApi(nil, WithCaptionCache(cache), WithEmptyBodyRetries(0), WithMinSnippetDuration(0.5))
+	if err != nil {
+		t.Fatalf("Failed to create API: %v", err)
+	}
+
+	transcript := NewTranscript(nil, testVideoID, "Test Video", "", "https://example.com/en", "English", "en", false, nil)
+	if transcript.captionOptions.cache != nil || transcript.captionOptions.emptyBodyRetries != DefaultEmptyBodyRetries {
+		t.Errorf("Expected default caption options, got %+v", transcript.captionOptions)
+	}
+
+	transcript.Bind(api)
+	opts := transcript.captionOptions
+	if opts.cache != cache || opts.emptyBodyRetries != 0 || opts.minSnippetDuration != 0.5 {
+		t.Errorf("Expected the bound instance's caption options, got %+v", opts)
+	}
+}
+
+// TestContentHash tests that the content hash only depends on the snippets
+func TestContentHash(t *testing.T) {
+	transcript := newTestFetchedTranscript()
+	hash := transcript.ContentHash()
+	if len(hash) != 64 {
+		t.Fatalf("Expected a hex SHA-256, got %q", hash)
+	}
+
+	// Metadata and float noise below a millisecond don't change the hash
+	other := newTestFetchedTranscript()
+	other.Title = "Another title"
+	other.LanguageCode = "de"
+	other.IsGenerated = !other.IsGenerated
+	other.Snippets[1].Start += 0.0001
+	if other.ContentHash() != hash {
+		t.Error("Expected metadata and sub-millisecond differences not to change the hash")
+	}
+
+	changes := map[string]func(ft *FetchedTranscript){
+		"text":     func(ft *FetchedTranscript) { ft.Snippets[0].Text = "Hello there!" },
+		"start":    func(ft *FetchedTranscript) { ft.Snippets[1].Start += 0.01 },
+		"duration": func(ft *FetchedTranscript) { ft.Snippets[1].Duration = 3 },
+		"order": func(ft *FetchedTranscript) {
+			ft.Snippets[0], ft.Snippets[1] = ft.Snippets[1], ft.Snippets[0]
+		},
+		"removed": func(ft *FetchedTranscript) { ft.Snippets = ft.Snippets[:1] },
+	}
+	for name, change := range changes {
+		changed := newTestFetchedTranscript()
+		change(changed)
+		if changed.ContentHash() == hash {
+			t.Errorf("Expected a %s change to change the hash", name)
+		}
+	}
+}
+
+// TestNewYouTubeTranscriptApiWithCookiesJSON tests importing browser-exported JSON cookies
+func TestNewYouTubeTranscriptApiWithCookiesJSON(t *testing.T) {
+	dir := t.TempDir()
+	writeCookies := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("Failed to write cookie file: %v", err)
+		}
+		return path
+	}
+
+	future := time.Now().Add(time.Hour).Unix()
+	path := writeCookies("cookies.json", fmt.Sprintf(`[
+		{"name":"SID","value":"abc","domain":".youtube.com","path":"/","expirationDate":%d.5,"secure":true},
+		{"name":"PREF","value":"hl=en","domain":"www.youtube.com","hostOnly":true},
+		{"name":"OLD","value":"x","domain":".youtube.com","expirationDate":1}
+	]`, future))
+
+	api, err := NewYouTubeTranscriptApiWithCookiesJSON(nil, path)
+	if err != nil {
+		t.Fatalf("Failed to create API: %v", err)
+	}
+	cookieNames := func() string {
+		var names []string
+		for _, cookie := range api.fetcher.httpClient.Jar.Cookies(&url.URL{Scheme: "https", Host: "www.youtube.com", Path: "/"}) {
+			names = append(names, cookie.Name+"="+cookie.Value)
+		}
+		return strings.Join(names, ";")
+	}
+	if got := cookieNames(); got != "SID=abc;PREF=hl=en" && got != "PREF=hl=en;SID=abc" {
+		t.Errorf("Expected SID and PREF cookies, got %q", got)
+	}
+
+	// Imported cookies survive Reset and are inherited by batch workers
+	if err := api.Reset(); err != nil {
+		t.Fatalf("Reset failed: %v", err)
+	}
+	if got := cookieNames(); !strings.Contains(got, "SID=abc") {
+		t.Errorf("Expected imported cookies after Reset, got %q", got)
+	}
+	worker, err := api.clone()
+	if err != nil {
+		t.Fatalf("Failed to clone API: %v", err)
+	}
+	if len(worker.fetcher.httpClient.Jar.Cookies(&url.URL{Scheme: "https", Host: "www.youtube.com"})) != 2 {
+		t.Error("Expected the cloned worker to have the imported cookies")
+	}
+
+	invalid := map[string]string{
+		"not-json.json":     `# Netscape HTTP Cookie File`,
+		"missing-name.json": `[{"value":"abc","domain":".youtube.com"}]`,
+		"all-expired.json":  `[{"name":"OLD","value":"x","domain":".youtube.com","expirationDate":1}]`,
+		"empty.json":        `[]`,
+		"not-an-array.json": `{"name":"SID"}`,
+	}
+	for name, content := range invalid {
+		if _, err := NewYouTubeTranscriptApiWithCookiesJSON(nil, writeCookies(name, content)); err == nil {
+			t.Errorf("%s: expected CookieInvalid, got nil", name)
+		} else if _, ok := err.(*CookieInvalid); !ok {
+			t.Errorf("%s: expected CookieInvalid, got %T: %v", name, err, err)
+		}
+	}
+
+	if _, err := NewYouTubeTranscriptApiWithCookiesJSON(nil, filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("Expected CookiePathInvalid for a missing file")
+	} else if _, ok := err.(*CookiePathInvalid); !ok {
+		t.Errorf("Expected CookiePathInvalid, got %T: %v", err, err)
+	}
+}
+
+// TestCLIOutputEncoding tests writing subtitle files with a BOM and as UTF-16
+func TestCLIOutputEncoding(t *testing.T) {
+	formats, err := loadCLIFormats("srt")
+	if err != nil {
+		t.Fatalf("Failed to load formats: %v", err)
+	}
+	transcripts := []*FetchedTranscript{newTestFetchedTranscript()}
+	srt, _ := NewSRTFormatter().FormatTranscript(transcripts[0])
+
+	tests := []struct {
+		encoding string
+		prefix   []byte
+	}{
+		{"", []byte(srt[:4])},
+		{OutputEncodingUTF8BOM, []byte{0xEF, 0xBB, 0xBF, srt[0]}},
+		{OutputEncodingUTF16LE, []byte{0xFF, 0xFE, srt[0], 0x00}},
+		{OutputEncodingUTF16BE, []byte{0xFE, 0xFF, 0x00, srt[0]}},
+	}
+	for _, tt := range tests {
+		dir := t.TempDir()
+		cli := NewYouTubeTranscriptCLI(CLIConfig{VideoIDs: []string{testVideoID}, OutputDir: dir, Encoding: tt.encoding})
+		if _, err := cli.writeDir(transcripts, nil, formats); err != nil {
+			t.Fatalf("%q: failed to write output: %v", tt.encoding, err)
+		}
+		data, err := os.ReadFile(filepath.Join(dir, testVideoID+".srt"))
+		if err != nil {
+			t.Fatalf("%q: failed to read output: %v", tt.encoding, err)
+		}
+		if len(data) < 4 || string(data[:4]) != string(tt.prefix) {
+			t.Errorf("%q: expected file to start with % x, got % x", tt.encoding, tt.prefix, data[:4])
+		}
+	}
+
+	if _, err := encodeOutput(srt, "latin-1"); err == nil {
+		t.Error("Expected error for unsupported encoding")
+	}
+}
+
+// TestCLIMultipleFormats tests comma-separated formats, extension collisions and the printed section headers
+func TestCLIMultipleFormats(t *testing.T) {
+	tests := []struct {
+		formatList string
+		files      []string
+		headers    []string
+	}{
+		{"srt", []string{testVideoID + ".srt"}, nil},
+		{"srt, vtt,SRT", []string{testVideoID + ".srt", testVideoID + ".vtt"}, []string{"==> srt <==", "==> webvtt <=="}},
+		{"pretty,txt,json", []string{testVideoID + ".pretty.json", testVideoID + ".txt", testVideoID + ".json.json"},
+			[]string{"==> pretty <==", "==> text <==", "==> json <=="}},
+	}
+
+	transcripts := []*FetchedTranscript{newTestFetchedTranscript()}
+	for _, tt := range tests {
+		formats, err := loadCLIFormats(tt.formatList)
+		if err != nil {
+			t.Fatalf("%q: failed to load formats: %v", tt.formatList, err)
+		}
+
+		var written []string
+		err = writeOutputs(transcripts, nil, formats, "", func(name string, content []byte) error {
+			written = append(written, name)
+			return nil
+		})
+		if err != nil || strings.Join(written, ",") != strings.Join(tt.files, ",") {
+			t.Errorf("%q: expected files %v, got %v (%v)", tt.formatList, tt.files, written, err)
+		}
+
+		sections, err := formatCLIOutputs(transcripts, formats)
+		if err != nil || len(sections) != len(formats) {
+			t.Fatalf("%q: expected %d sections, got %d (%v)", tt.formatList, len(formats), len(sections), err)
+		}
+		for i, section := range sections {
+			if tt.headers == nil {
+				if strings.HasPrefix(section, "==>") {
+					t.Errorf("%q: expected no header for a single format, got %q", tt.formatList, section)
+				}
+			} else if !strings.HasPrefix(section, tt.headers[i]+"\n") {
+				t.Errorf("%q: section %d should start with %q, got %q", tt.formatList, i, tt.headers[i], section)
+			}
+		}
+	}
+
+	for _, formatList := range []string{"srt,bogus", " , "} {
+		if _, err := loadCLIFormats(formatList); err == nil {
+			t.Errorf("%q: expected an error", formatList)
+		}
+	}
+}
+
+// TestCLIGrep tests the grep output lines and that an invalid pattern fails before any request is made
+func TestCLIGrep(t *testing.T) {
+	transcripts := []*FetchedTranscript{
+		{VideoID: testVideoID, Snippets: []FetchedTranscriptSnippet{{Text: "Hello\nthere", Start: 3723.5, Duration: 1}}},
+		{VideoID: altTestVideoID, Snippets: []FetchedTranscriptSnippet{{Text: "General Kenobi", Start: 1.25, Duration: 1}}},
+	}
+	expected := testVideoID + " [01:02:03.500] Hello there\n" + altTestVideoID + " [00:00:01.250] General Kenobi"
+	if got := formatGrepMatches(transcripts); got != expected {
+		t.Errorf("Unexpected grep output:\n%s\nexpected:\n%s", got, expected)
+	}
+
+	var requests int32
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer proxy.Close()
+	cli := NewYouTubeTranscriptCLI(CLIConfig{VideoIDs: []string{testVideoID}, Grep: "(", HTTPProxy: proxy.URL, HTTPSProxy: proxy.URL})
+	if _, err := cli.Run(); err == nil || !strings.Contains(err.Error(), "invalid grep pattern") {
+		t.Errorf("Expected an invalid grep pattern error, got %v", err)
+	}
+	if atomic.LoadInt32(&requests) != 0 {
+		t.Errorf("Expected no requests for an invalid pattern, got %d", requests)
+	}
+}
+
+// TestCLIGzipOutput tests that gzip output files decompress to the formatted transcript and need an output directory
+func TestCLIGzipOutput(t *testing.T) {
+	formats, err := loadCLIFormats("srt")
+	if err != nil {
+		t.Fatalf("Failed to load formats: %v", err)
+	}
+	transcript := newTestFetchedTranscript()
+	dir := t.TempDir()
+	cli := NewYouTubeTranscriptCLI(CLIConfig{VideoIDs: []string{testVideoID}, OutputDir: dir, Gzip: true})
+	if _, err := cli.writeDir([]*FetchedTranscript{transcript}, nil, formats); err != nil {
+		t.Fatalf("Failed to write output: %v", err)
+	}
+
+	file, err := os.Open(filepath.Join(dir, testVideoID+".srt.gz"))
+	if err != nil {
+		t.Fatalf("Failed to open gzip output: %v", err)
+	}
+	defer file.Close()
+	gzipReader, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatalf("Failed to read gzip header: %v", err)
+	}
+	data, err := io.ReadAll(gzipReader)
+	if err != nil {
+		t.Fatalf("Failed to decompress output: %v", err)
+	}
+	if srt, _ := NewSRTFormatter().FormatTranscript(transcript); string(data) != srt {
+		t.Errorf("Unexpected decompressed output: %q", data)
+	}
+	if _, err := os.Stat(filepath.Join(dir, testVideoID+".srt")); !os.IsNotExist(err) {
+		t.Error("Expected no uncompressed file")
+	}
+
+	cli = NewYouTubeTranscriptCLI(CLIConfig{VideoIDs: []string{testVideoID}, Gzip: true, OutputZip: filepath.Join(dir, "out.zip")})
+	if _, err := cli.Run(); err == nil || !strings.Contains(err.Error(), "output directory") {
+		t.Errorf("Expected an error for gzip without an output directory, got %v", err)
+	}
+
+	cli = NewYouTubeTranscriptCLI(CLIConfig{VideoIDs: []string{testVideoID}, Encoding: OutputEncodingUTF8BOM})
+	if _, err := cli.Run(); err == nil || !strings.Contains(err.Error(), "output directory or zip file") {
+		t.Errorf("Expected an error for an encoding without an output directory or zip file, got %v", err)
+	}
+}
+
+// TestCLIDefaultLanguages tests that the CLI falls back to DefaultLanguages when no languages are given
+func TestCLIDefaultLanguages(t *testing.T) {
+	cli := NewYouTubeTranscriptCLI(CLIConfig{VideoIDs: []string{testVideoID}})
+	if strings.Join(cli.config.Languages, " ") != strings.Join(DefaultLanguages, " ") {
+		t.Errorf("Expected languages %v, got %v", DefaultLanguages, cli.config.Languages)
+	}
+}
+
+// TestCLIListTranscriptsRejectsOutputFiles tests that file output flags are rejected in list mode instead of ignored
+func TestCLIListTranscriptsRejectsOutputFiles(t *testing.T) {
+	dir := t.TempDir()
+	configs := map[string]CLIConfig{
+		"zip":      {OutputZip: filepath.Join(dir, "out.zip")},
+		"dir":      {OutputDir: dir},
+		"gzip":     {OutputDir: dir, Gzip: true},
+		"encoding": {OutputZip: filepath.Join(dir, "out.zip"), Encoding: OutputEncodingUTF16LE},
+	}
+	for name, config := range configs {
+		config.VideoIDs = []string{testVideoID}
+		config.ListTranscripts = true
+		if _, err := NewYouTubeTranscriptCLI(config).Run(); err == nil || !strings.Contains(err.Error(), "listing transcripts") {
+			t.Errorf("%s: expected an error for output files in list mode, got %v", name, err)
+		}
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("Expected no files to be written, got %d", len(entries))
+	}
+}
+
+// TestCLIListFormatIsJSON tests resolving the list mode output format
+func TestCLIListFormatIsJSON(t *testing.T) {
+	tests := map[string]bool{
+		"json":      true,
+		" JSON ":    true,
+		"srt, json": true,
+		"pretty":    false,
+		"txt,vtt":   false,
+		"jsonl":     false,
+		"":          false,
+	}
+	for formatList, expected := range tests {
+		if got := listFormatIsJSON(formatList); got != expected {
+			t.Errorf("listFormatIsJSON(%q) = %v, expected %v", formatList, got, expected)
+		}
+	}
+}
+
+// TestCLIWriteZip tests the zip entries written for transcripts and errors, and that a failed write leaves no zip behind
+func TestCLIWriteZip(t *testing.T) {
+	formats, err := loadCLIFormats("srt,json")
+	if err != nil {
+		t.Fatalf("Failed to load formats: %v", err)
+	}
+	transcript := newTestFetchedTranscript()
+	path := filepath.Join(t.TempDir(), "out.zip")
+	cli := NewYouTubeTranscriptCLI(CLIConfig{VideoIDs: []string{testVideoID}, OutputZip: path})
+	if _, err := cli.writeZip([]*FetchedTranscript{transcript}, []error{NewTranscriptsDisabled(altTestVideoID)}, formats); err != nil {
+		t.Fatalf("Failed to write zip: %v", err)
+	}
+
+	reader, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatalf("Failed to open zip: %v", err)
+	}
+	defer reader.Close()
+	entries := map[string]string{}
+	for _, file := range reader.File {
+		rc, err := file.Open()
+		if err != nil {
+			t.Fatalf("Failed to open entry %s: %v", file.Name, err)
+		}
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		entries[file.Name] = string(data)
+	}
+	srt, _ := NewSRTFormatter().FormatTranscript(transcript)
+	if len(entries) != 3 || entries[testVideoID+".srt"] != srt || entries[testVideoID+".json"] == "" ||
+		!strings.Contains(entries["errors.txt"], altTestVideoID) {
+		t.Errorf("Unexpected zip entries: %v", entries)
+	}
+
+	// A nil transcript makes the formatter fail after the first entry was written
+	failedPath := filepath.Join(t.TempDir(), "failed.zip")
+	cli = NewYouTubeTranscriptCLI(CLIConfig{VideoIDs: []string{testVideoID}, OutputZip: failedPath})
+	if _, err := cli.writeZip([]*FetchedTranscript{transcript, nil}, nil, formats); err == nil {
+		t.Error("Expected an error for a failed write")
+	}
+	if _, err := os.Stat(failedPath); !os.IsNotExist(err) {
+		t.Errorf("Expected the partial zip to be removed, got %v", err)
+	}
+}
+
+// TestBatchDedupesVideoIDs tests that repeated video IDs are fetched once and share their result
+func TestBatchDedupesVideoIDs(t *testing.T) {
+	unique, indexes := dedupeVideoIDs([]string{"a", "b", "a", "c", "b"})
+	if strings.Join(unique, ",") != "a,b,c" || fmt.Sprint(indexes) != "[0 1 0 2 1]" {
+		t.Errorf("Unexpected dedupe result: %v %v", unique, indexes)
+	}
+
+	// Route every request to a local server that serves a watch page without an API key
+	var mu sync.Mutex
+	requested := map[string]int{}
+	server, transport := newFakeYouTubeServer(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested[r.URL.Query().Get("v")]++
+		mu.Unlock()
+		fmt.Fprint(w, "<html></html>")
+	})
+	defer server.Close()
+
+	videoIDs := []string{testVideoID, altTestVideoID, testVideoID}
+	api, err := NewYouTubeTranscriptApi(nil, WithSharedTransport(transport))
+	if err != nil {
+		t.Fatalf("Failed to create API: %v", err)
+	}
+	results := api.FetchBatch(context.Background(), videoIDs, []string{"en"}, false, 2)
+	if len(results) != len(videoIDs) {
+		t.Fatalf("Expected %d results, got %d", len(videoIDs), len(results))
+	}
+	for i, result := range results {
+		if result.VideoID != videoIDs[i] {
+			t.Errorf("Result %d: expected %s, got %s", i, videoIDs[i], result.VideoID)
+		}
+		if _, ok := result.Err.(*YouTubeDataUnparsable); !ok {
+			t.Errorf("Result %d: expected YouTubeDataUnparsable, got %T: %v", i, result.Err, result.Err)
+		}
+	}
+	if results[0].Err != results[2].Err {
+		t.Error("Expected duplicate IDs to share the same result")
+	}
+	if requested[testVideoID] != 1 || requested[altTestVideoID] != 1 {
+		t.Errorf("Expected each video to be requested once, got %v", requested)
+	}
+
+	if streamed := collectBatch(api.FetchStreamBatch(context.Background(), videoIDs, []string{"en"}, false, 2)); len(streamed) != len(videoIDs) {
+		t.Errorf("Expected %d streamed results, got %d", len(videoIDs), len(streamed))
+	}
+
+	requested = map[string]int{}
+	api, err = NewYouTubeTranscriptApi(nil, WithSharedTransport(transport), WithBatchDuplicates())
+	if err != nil {
+		t.Fatalf("Failed to create API: %v", err)
+	}
+	api.ListBatch(context.Background(), videoIDs, 1)
+	if requested[testVideoID] != 2 {
+		t.Errorf("Expected duplicates to be requested twice with WithBatchDuplicates, got %v", requested)
+	}
+}
+
+// TestFetchedTranscript_SortBy tests custom snippet orderings without mutating the original
+func TestFetchedTranscript_SortBy(t *testing.T) {
+	transcript := newTestFetchedTranscript()
+
+	reversed := transcript.SortBy(func(a, b FetchedTranscriptSnippet) bool { return a.Start > b.Start })
+	if reversed.Snippets[0].Text != "General Kenobi" || reversed.Snippets[1].Text != "Hello there" {
+		t.Errorf("Expected reverse chronological order, got %+v", reversed.Snippets)
+	}
+	if reversed.VideoID != transcript.VideoID {
+		t.Error("Expected metadata to be copied")
+	}
+	if transcript.Snippets[0].Text != "Hello there" {
+		t.Error("SortBy should not modify the original transcript")
+	}
+
+	byLength := transcript.SortBy(func(a, b FetchedTranscriptSnippet) bool { return len(a.Text) > len(b.Text) })
+	if byLength.Snippets[0].Text != "General Kenobi" {
+		t.Errorf("Expected longest text first, got %+v", byLength.Snippets)
+	}
+}
+
+// TestTranscriptLifecycle tests fetching after Reset, after Close and after rebinding with Bind
+func TestTranscriptLifecycle(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, testTranscriptXML)
+	}))
+	defer server.Close()
+
+	api, err := NewYouTubeTranscriptApi(nil)
+	if err != nil {
+		t.Fatalf("Failed to create API: %v", err)
+	}
+	transcriptList := &TranscriptList{
+		VideoID: testVideoID,
+		manuallyCreatedTranscripts: map[string]*Transcript{
+			"en": NewTranscript(api.fetcher.httpClient, testVideoID, "Test Video", "", server.URL+"/api/timedtext", "English", "en", false, nil),
+		},
+		generatedTranscripts: map[string]*Transcript{},
+	}
+	transcript := transcriptList.manuallyCreatedTranscripts["en"]
+
+	// A transcript stays fetchable after the instance is reset
+	if err := api.Reset(); err != nil {
+		t.Fatalf("Reset failed: %v", err)
+	}
+	if _, err := transcript.Fetch(false); err != nil {
+		t.Fatalf("Expected fetch after Reset to succeed, got %v", err)
+	}
+
+	api.Close()
+	if _, err := transcript.Fetch(false); err != ErrClientClosed {
+		t.Errorf("Expected ErrClientClosed after Close, got %v", err)
+	}
+	if _, err := api.List(testVideoID); err != ErrClientClosed {
+		t.Errorf("Expected ErrClientClosed from List after Close, got %v", err)
+	}
+	if err := api.Reset(); err != ErrClientClosed {
+		t.Errorf("Expected Reset after Close to fail with ErrClientClosed, got %v", err)
+	}
+
+	other, err := NewYouTubeTranscriptApi(nil)
+	if err != nil {
+		t.Fatalf("Failed to create API: %v", err)
+	}
+	transcriptList.Bind(other)
+	if _, err := transcript.Fetch(false); err != nil {
+		t.Errorf("Expected fetch after Bind to succeed, got %v", err)
+	}
+}
+
+// TestTranscriptList_FindTranscriptStrict tests exact language and kind matching without fallbacks
+func TestTranscriptList_FindTranscriptStrict(t *testing.T) {
+	transcriptList := newTestTranscriptList()
+
+	// Only manual transcripts by default, so generated English is never used as a fallback
+	transcript, err := transcriptList.FindTranscriptStrict([]string{"en"})
+	if err != nil || transcript.IsGenerated {
+		t.Fatalf("Expected the manual English transcript, got %+v, %v", transcript, err)
+	}
+
+	transcript, err = transcriptList.FindTranscriptStrict([]string{"en"}, TranscriptKindGenerated)
+	if err != nil || !transcript.IsGenerated {
+		t.Errorf("Expected the generated English transcript, got %+v, %v", transcript, err)
+	}
+	if _, err := transcriptList.FindTranscriptStrict([]string{"es"}, TranscriptKindGenerated); err == nil {
+		t.Error("Expected NoTranscriptFound for a manual-only language when asking for generated")
+	}
+
+	// Codes must match exactly: no case folding, region fallback or translation directives
+	for _, codes := range [][]string{{"EN"}, {"en-US"}, {AutoTranslatePrefix + "de"}} {
+		_, err := transcriptList.FindTranscriptStrict(codes, TranscriptKindManual, TranscriptKindGenerated)
+		if _, ok := err.(*NoTranscriptFound); !ok {
+			t.Errorf("%v: expected NoTranscriptFound, got %v", codes, err)
+		}
+	}
+
+	// The first language wins over kind priority
+	transcript, err = transcriptList.FindTranscriptStrict([]string{"es", "en"}, TranscriptKindGenerated, TranscriptKindManual)
+	if err != nil || transcript.LanguageCode != "es" {
+		t.Errorf("Expected the Spanish transcript, got %+v, %v", transcript, err)
+	}
+}
+
+// TestHashedProxyPool tests that videos are pinned to a proxy and that requests pick it from their context
+func TestHashedProxyPool(t *testing.T) {
+	if _, err := NewHashedProxyPool(nil); err == nil {
+		t.Error("Expected error for empty proxy pool")
+	}
+	if _, err := NewHashedProxyPool([]string{"not a proxy"}); err == nil {
+		t.Error("Expected error for invalid proxy URL")
+	}
+
+	proxyURLs := []string{"http://proxy-a:8080", "http://proxy-b:8080", "http://proxy-c:8080"}
+	pool, err := NewHashedProxyPool(proxyURLs)
+	if err != nil {
+		t.Fatalf("Failed to create proxy pool: %v", err)
+	}
+
+	assigned := map[string]string{}
+	used := map[string]bool{}
+	for i := 0; i < 60; i++ {
+		videoID := fmt.Sprintf("video%07d", i)
+		proxyURL := pool.ProxyURLForVideo(videoID)
+		if proxyURL != pool.ProxyURLForVideo(videoID) {
+			t.Fatalf("Expected stable proxy for %s", videoID)
+		}
+		assigned[videoID] = proxyURL
+		used[proxyURL] = true
+	}
+	if len(used) != len(proxyURLs) {
+		t.Errorf("Expected videos to spread across all %d proxies, got %d", len(proxyURLs), len(used))
+	}
+
+	// Removing a proxy only moves the videos that were on it
+	smaller, err := NewHashedProxyPool(proxyURLs[:2])
+	if err != nil {
+		t.Fatalf("Failed to create proxy pool: %v", err)
+	}
+	for videoID, proxyURL := range assigned {
+		if proxyURL != proxyURLs[2] && smaller.ProxyURLForVideo(videoID) != proxyURL {
+			t.Errorf("Expected %s to stay on %s after removing another proxy", videoID, proxyURL)
+		}
+	}
+
+	api, err := NewYouTubeTranscriptApi(pool)
+	if err != nil {
+		t.Fatalf("Failed to create API: %v", err)
+	}
+	client := api.fetcher.httpClient
+	req, _ := http.NewRequest("GET", "https://www.youtube.com/watch?v="+testVideoID, nil)
+	proxyURL, err := client.proxyForRequest(req)
+	if err != nil || proxyURL.String() != proxyURLs[0] {
+		t.Errorf("Expected requests without a video to use %s, got %v (%v)", proxyURLs[0], proxyURL, err)
+	}
+	req = req.WithContext(withRequestVideoID(req.Context(), testVideoID))
+	proxyURL, err = client.proxyForRequest(req)
+	if err != nil || proxyURL.String() != pool.ProxyURLForVideo(testVideoID) {
+		t.Errorf("Expected %s, got %v (%v)", pool.ProxyURLForVideo(testVideoID), proxyURL, err)
+	}
+}
+
+// TestTranscriptList_DefaultTranscript tests picking the default track and the manual/generated fallbacks
+func TestTranscriptList_DefaultTranscript(t *testing.T) {
+	transcriptList, err := BuildTranscriptList(nil, testVideoID,
+		map[string]interface{}{"title": "Test Video"},
+		map[string]interface{}{
+			"captionTracks": []interface{}{
+				map[string]interface{}{"baseUrl": "https://example.com/en", "languageCode": "en"},
+				map[string]interface{}{"baseUrl": "https://example.com/ja-asr", "languageCode": "ja", "kind": "asr"},
+			},
+			"audioTracks": []interface{}{
+				map[string]interface{}{"defaultCaptionTrackIndex": float64(0)},
+				map[string]interface{}{"defaultCaptionTrackIndex": float64(1)},
+			},
+			"defaultAudioTrackIndex": float64(1),
+		})
+	if err != nil {
+		t.Fatalf("Failed to build transcript list: %v", err)
+	}
+	transcript, err := transcriptList.DefaultTranscript()
+	if err != nil || transcript.LanguageCode != "ja" || !transcript.IsGenerated {
+		t.Errorf("Expected the default generated ja track, got %v (%v)", transcript, err)
+	}
+
+	transcript, err = newTestTranscriptList().DefaultTranscript()
+	if err != nil || transcript.LanguageCode != "en" || transcript.IsGenerated {
+		t.Errorf("Expected the first manual track, got %v (%v)", transcript, err)
+	}
+
+	generatedOnly := NewTranscriptList(testVideoID, map[string]*Transcript{}, map[string]*Transcript{
+		"en": NewTranscript(nil, testVideoID, "Test Video", "", "https://example.com/en-asr", "English (auto-generated)", "en", true, nil),
+	}, nil)
+	transcript, err = generatedOnly.DefaultTranscript()
+	if err != nil || !transcript.IsGenerated {
+		t.Errorf("Expected the generated track, got %v (%v)", transcript, err)
+	}
+
+	_, err = NewTranscriptList(testVideoID, map[string]*Transcript{}, map[string]*Transcript{}, nil).DefaultTranscript()
+	if _, ok := err.(*NoTranscriptFound); !ok {
+		t.Errorf("Expected NoTranscriptFound for an empty list, got %v", err)
+	}
+}
+
+// TestTranscriptList_MarshalJSON tests the deterministic JSON form of a transcript list
+func TestTranscriptList_MarshalJSON(t *testing.T) {
+	transcriptList := newTestTranscriptList()
+	transcriptList.translationLanguages = []TranslationLanguage{
+		{Language: "French", LanguageCode: "fr"},
+		{Language: "German", LanguageCode: "de"},
+	}
+
+	data, err := json.Marshal(transcriptList)
+	if err != nil {
+		t.Fatalf("Failed to marshal transcript list: %v", err)
+	}
+	expected := `{"video_id":"` + testVideoID + `",` +
+		`"manual":[{"language_code":"en","language":"English","is_translatable":true},{"language_code":"es","language":"Spanish"}],` +
+		`"generated":[{"language_code":"en","language":"English (auto-generated)","is_translatable":true}],` +
+		`"translation_languages":[{"language_code":"de","language":"German"},{"language_code":"fr","language":"French"}]}`
+	if string(data) != expected {
+		t.Errorf("Expected %s, got %s", expected, data)
+	}
+
+	for i := 0; i < 5; i++ {
+		again, _ := json.Marshal(transcriptList)
+		if string(again) != string(data) {
+			t.Fatalf("Expected stable output, got %s", again)
+		}
+	}
+
+	empty, err := formatTranscriptListsJSON(nil)
+	if err != nil || empty != "[]" {
+		t.Errorf("Expected an empty JSON array, got %q (%v)", empty, err)
+	}
+}
+
+// TestWithRequestHeaders tests that context headers are sent and override the static headers
+func TestWithRequestHeaders(t *testing.T) {
+	received := make(chan http.Header, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r.Header.Clone()
+	}))
+	defer server.Close()
+
+	api, err := NewYouTubeTranscriptApi(nil)
+	if err != nil {
+		t.Fatalf("Failed to create API: %v", err)
+	}
+	client := api.fetcher.httpClient
+
+	ctx := WithRequestHeaders(context.Background(), map[string]string{"X-Request-Id": "first", "Accept-Language": "de-DE"})
+	ctx = WithRequestHeaders(ctx, map[string]string{"X-Request-Id": "second"})
+	resp, err := client.GetContext(ctx, server.URL)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+	header := <-received
+	if header.Get("X-Request-Id") != "second" || header.Get("Accept-Language") != "de-DE" {
+		t.Errorf("Expected context headers to override static headers, got %v", header)
+	}
+
+	resp, err = client.postWithHeaders(ctx, server.URL, "application/json", strings.NewReader("{}"), map[string]string{"X-Request-Id": "internal"})
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+	header = <-received
+	if header.Get("X-Request-Id") != "internal" || header.Get("Content-Type") != "application/json" {
+		t.Errorf("Expected request-specific headers to take precedence, got %v", header)
+	}
+
+	resp, err = client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+	header = <-received
+	if header.Get("X-Request-Id") != "" || header.Get("Accept-Language") != "en-US" {
+		t.Errorf("Expected only static headers without a context, got %v", header)
+	}
+}
+
+// TestTranscriptParser_StripCueTokens tests removing inline position/alignment cue tokens
+func TestTranscriptParser_StripCueTokens(t *testing.T) {
+	rawData := `<?xml version="1.0" encoding="utf-8" ?><transcript>` +
+		`<text start="0.0" dur="1.5">{ALIGN:START}Hello there</text>` +
+		`<text start="1.5" dur="1.0">{\an8}General &lt;i&gt;Kenobi&lt;/i&gt;</text>` +
+		`<text start="2.5" dur="1.0">{POSITION:10%}</text>` +
+		`<text start="3.5" dur="1.0">{laughs} You are a bold one</text>` +
+		`</transcript>`
+
+	snippets, err := NewTranscriptParser(false).Parse(rawData)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	expected := []string{"Hello there", "General Kenobi", "{laughs} You are a bold one"}
+	if len(snippets) != len(expected) {
+		t.Fatalf("Expected %d snippets, got %d: %+v", len(expected), len(snippets), snippets)
+	}
+	for i, text := range expected {
+		if snippets[i].Text != text {
+			t.Errorf("Snippet %d: expected %q, got %q", i, text, snippets[i].Text)
+		}
+	}
+
+	snippets, err = NewTranscriptParser(true).Parse(rawData)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	if len(snippets) != 4 || snippets[0].Text != "{ALIGN:START}Hello there" || snippets[1].Text != `{\an8}General <i>Kenobi</i>` {
+		t.Errorf("Expected cue tokens to be kept when preserving formatting, got %+v", snippets)
+	}
+
+	snippets, err = NewTranscriptParser(false).WithStripCueTokens(false).Parse(rawData)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	if len(snippets) != 4 || snippets[2].Text != "{POSITION:10%}" {
+		t.Errorf("Expected cue tokens to be kept, got %+v", snippets)
+	}
+}
+
+// TestFormatterLoader_FormatCatalog tests the structured list of registered formats
+func TestFormatterLoader_FormatCatalog(t *testing.T) {
+	loader := NewFormatterLoader()
+	catalog := loader.FormatCatalog()
+
+	names := make([]string, 0, len(catalog))
+	for _, info := range catalog {
+		names = append(names, info.Name)
+		if info.Extension == "" || info.MIMEType == "" || info.Description == "" {
+			t.Errorf("Expected complete metadata for %s, got %+v", info.Name, info)
+		}
+		extension, err := loader.Extension(info.Name)
+		if err != nil || "."+extension != info.Extension {
+			t.Errorf("Expected Extension(%s) to match %s, got %s (%v)", info.Name, info.Extension, extension, err)
+		}
+	}
+	if strings.Join(names, ",") != "csv,json,pretty,srt,text,webvtt" {
+		t.Errorf("Expected sorted built-in formats, got %v", names)
+	}
+
+	for _, info := range catalog {
+		if info.Name == "srt" && (info.Extension != ".srt" || info.MIMEType != "application/x-subrip") {
+			t.Errorf("Unexpected srt metadata: %+v", info)
+		}
+		if info.Name == "pretty" && (info.Extension != ".json" || info.MIMEType != "application/json") {
+			t.Errorf("Expected pretty output to be described as JSON, got %+v", info)
+		}
+	}
+}
+
+// TestBatchLimits tests that batch fetches stop with LimitExceeded once a cumulative limit is exceeded
+func TestBatchLimits(t *testing.T) {
+	api, err := NewYouTubeTranscriptApi(nil)
+	if err != nil {
+		t.Fatalf("Failed to create API: %v", err)
+	}
+	if api.newBatchLimits() != nil {
+		t.Error("Expected batches to be unlimited by default")
+	}
+
+	api, err = NewYouTubeTranscriptApi(nil, WithBatchLimits(3, 100))
+	if err != nil {
+		t.Fatalf("Failed to create API: %v", err)
+	}
+	limits := api.newBatchLimits()
+	if limits.exceeded() != "" {
+		t.Error("Expected fresh limits not to be exceeded")
+	}
+
+	// Reaching the limit is allowed, only exceeding it stops the batch
+	atomic.AddInt64(&limits.snippets, 3)
+	if limits.exceeded() != "" {
+		t.Error("Expected the snippet limit to be inclusive")
+	}
+	atomic.AddInt64(&limits.snippets, 1)
+	// A nil worker proves that no request is made once the limit is exceeded
+	_, err = limits.fetch(context.Background(), nil, testVideoID, []string{"en"}, false)
+	limitErr, ok := err.(*LimitExceeded)
+	if !ok || limitErr.Limit != "snippets" || limitErr.VideoID != testVideoID || ErrorCode(err) != "LIMIT_EXCEEDED" ||
+		!strings.Contains(err.Error(), "snippets limit") || !strings.Contains(err.Error(), "This is most likely caused by") {
+		t.Errorf("Expected LimitExceeded on snippets, got %T: %v", err, err)
+	}
+
+	limits = api.newBatchLimits()
+	atomic.AddInt64(&limits.bytes, 101)
+	if _, err := limits.fetch(context.Background(), nil, testVideoID, nil, false); err == nil || err.(*LimitExceeded).Limit != "bytes" {
+		t.Errorf("Expected LimitExceeded on bytes, got %v", err)
+	}
+}
+
+// TestFetchArchive tests capturing the raw payloads and replaying the extraction offline
+func TestFetchArchive(t *testing.T) {
+	playerResponse := `{"playabilityStatus":{"status":"OK"},"videoDetails":{"title":"Test Video"},` +
+		`"captions":{"playerCaptionsTracklistRenderer":{"captionTracks":[` +
+		`{"baseUrl":"https://www.youtube.com/api/timedtext?v=` + testVideoID + `&lang=en","languageCode":"en","name":{"runs":[{"text":"English"}]},"isTranslatable":true}],` +
+		`"translationLanguages":[{"languageCode":"de","languageName":{"runs":[{"text":"German"}]}}]}}}`
+	server, transport := newFakeYouTubeServer(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/watch":
+			fmt.Fprint(w, `<html><script>ytcfg.set({"INNERTUBE_API_KEY":"testkey"});</script></html>`)
+		case "/youtubei/v1/player":
+			fmt.Fprint(w, playerResponse)
+		case "/api/timedtext":
+			fmt.Fprint(w, testTranscriptXML)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	defer server.Close()
+
+	api, err := NewYouTubeTranscriptApi(nil, WithSharedTransport(transport))
+	if err != nil {
+		t.Fatalf("Failed to create API: %v", err)
+	}
+	bundle, err := api.FetchArchive(testVideoID, []string{"en"})
+	if err != nil {
+		t.Fatalf("FetchArchive failed: %v", err)
+	}
+	if !strings.Contains(bundle.WatchHTML, "INNERTUBE_API_KEY") || string(bundle.InnertubeJSON) != playerResponse ||
+		bundle.CaptionBody != testTranscriptXML || bundle.LanguageCode != "en" || !strings.Contains(bundle.CaptionURL, "/api/timedtext") {
+		t.Errorf("Unexpected archive bundle: %+v", bundle)
+	}
+
+	// The bundle survives a JSON round trip and replays without any requests
+	data, err := json.Marshal(bundle)
+	if err != nil {
+		t.Fatalf("Failed to marshal bundle: %v", err)
+	}
+	var restored ArchiveBundle
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Fatalf("Failed to unmarshal bundle: %v", err)
+	}
+	server.Close()
+	replayed, err := restored.Replay(false)
+	if err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+	if replayed.Title != "Test Video" || replayed.Language != "English" || len(replayed.Snippets) != 2 || replayed.Snippets[1].Text != "General Kenobi" {
+		t.Errorf("Unexpected replayed transcript: %+v", replayed)
+	}
+
+	restored.SourceLanguageCode, restored.LanguageCode = "en", "de"
+	if translated, err := restored.Replay(false); err != nil || translated.LanguageCode != "de" || translated.SourceLanguageCode != "en" {
+		t.Errorf("Expected a translated replay, got %+v (%v)", translated, err)
+	}
+
+	// Caption text mentioning captcha phrases is still parsed as captions
+	restored.SourceLanguageCode, restored.LanguageCode = "", "en"
+	restored.CaptionBody = testCaptchaWordingXML
+	if replayed, err := restored.Replay(false); err != nil || len(replayed.Snippets) != 1 {
+		t.Errorf("Expected caption text with captcha wording to replay, got %+v (%v)", replayed, err)
+	}
+
+	restored.InnertubeJSON = nil
+	if _, err := restored.Replay(false); err == nil {
+		t.Error("Expected an error for an incomplete bundle")
+	}
+}
+
+// TestWithTextTransform tests transforming snippet text at format time without mutating the transcript
+func TestWithTextTransform(t *testing.T) {
+	transcript := newTestFetchedTranscript()
+	formatter := WithTextTransform(NewSRTFormatter(), strings.ToUpper)
+
+	output, err := formatter.FormatTranscript(transcript)
+	if err != nil {
+		t.Fatalf("Failed to format: %v", err)
+	}
+	expected := "1\n00:00:00,000 --> 00:00:01,500\nHELLO THERE\n\n2\n00:00:01,500 --> 00:00:03,500\nGENERAL KENOBI\n"
+	if output != expected {
+		t.Errorf("Expected %q, got %q", expected, output)
+	}
+	if transcript.Snippets[0].Text != "Hello there" {
+		t.Error("WithTextTransform should not modify the transcript")
+	}
+
+	if _, err := formatter.FormatTranscript(nil); err != ErrNilTranscript {
+		t.Errorf("Expected ErrNilTranscript, got %v", err)
+	}
+	output, err = formatter.FormatTranscripts([]*FetchedTranscript{transcript, nil})
+	if err != nil || !strings.Contains(output, "GENERAL KENOBI") {
+		t.Errorf("Expected transformed output for several transcripts, got %q (%v)", output, err)
+	}
+}
+
+// TestTranscriptParser_InvalidUTF8 tests that invalid UTF-8 bytes are replaced instead of failing the parse
+func TestTranscriptParser_InvalidUTF8(t *testing.T) {
+	rawData := `<?xml version="1.0" encoding="utf-8" ?><transcript>` +
+		"<text start=\"0.0\" dur=\"1.5\">Hello \xff\xfethere</text>" +
+		`<text start="1.5" dur="2.0">General Kenobi</text>` +
+		`</transcript>`
+
+	snippets, err := NewTranscriptParser(false).Parse(rawData)
+	if err != nil {
+		t.Fatalf("Expected invalid UTF-8 to be tolerated, got %v", err)
+	}
+	if len(snippets) != 2 || snippets[0].Text != "Hello �there" || snippets[1].Text != "General Kenobi" {
+		t.Errorf("Unexpected snippets: %+v", snippets)
+	}
+}
+
+// TestFetchedTranscript_Speakers tests detecting distinct speaker labels
+func TestFetchedTranscript_Speakers(t *testing.T) {
+	transcript := &FetchedTranscript{Snippets: []FetchedTranscriptSnippet{
+		{Text: ">> OBI-WAN: Hello there", Start: 0, Duration: 1},
+		{Text: ">> General Grievous: General Kenobi", Start: 1, Duration: 1},
+		{Text: "OBI-WAN: You are a bold one\n- DR. JONES: at 10:30", Start: 2, Duration: 1},
+		{Text: ">> obi-wan: again >> GRIEVOUS: Kill him", Start: 3, Duration: 1},
+		{Text: "The time is 10:30: note that lower case: is ignored", Start: 4, Duration: 1},
+		{Text: ">> Moving on", Start: 5, Duration: 1},
+		{Text: ">> and then he said: hi", Start: 6, Duration: 1},
+		{Text: ">> So then We: lower case words break title case", Start: 7, Duration: 1},
+	}}
+
+	expected := []string{"OBI-WAN", "General Grievous", "DR. JONES", "GRIEVOUS"}
+	if speakers := transcript.Speakers(); strings.Join(speakers, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected %v, got %v", expected, speakers)
+	}
+
+	if speakers := newTestFetchedTranscript().Speakers(); speakers == nil || len(speakers) != 0 {
+		t.Errorf("Expected an empty slice without labels, got %#v", speakers)
+	}
+}
@@ -0,0 +1,141 @@
+package youtube_transcript_api
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// ArchiveBundle FetchArchive 得到的原始数据，可序列化为 JSON 保存，之后通过 Replay 离线重新提取字幕
+// 包含完整的视频页面 HTML（通常超过 1 MB）和 Innertube player 响应（通常数百 KB），单个视频的体积远大于字幕本身，
+// 大量归档时建议压缩保存
+type ArchiveBundle struct {
+	VideoID   string    `json:"video_id"`
+	FetchedAt time.Time `json:"fetched_at"`
+	// InnertubeClient 请求 Innertube player 接口时使用的客户端名称（如 "ANDROID"）
+	InnertubeClient string `json:"innertube_client"`
+	// WatchHTML 视频页面 HTML（与提取 API Key 时使用的内容一致，HTML 实体已解码）
+	WatchHTML string `json:"watch_html"`
+	// InnertubeJSON Innertube player 接口的原始响应体
+	InnertubeJSON json.RawMessage `json:"innertube_json"`
+	// CaptionURL、LanguageCode、IsGenerated 按 languages 选中的字幕轨道
+	CaptionURL   string `json:"caption_url"`
+	LanguageCode string `json:"language_code"`
+	IsGenerated  bool   `json:"is_generated"`
+	// SourceLanguageCode 选中的是翻译字幕（"auto-translate:" 指令）时为源字幕的语言代码，否则为空
+	SourceLanguageCode string `json:"source_language_code,omitempty"`
+	// CaptionBody 字幕 URL 的原始响应体（未解析的 XML）
+	CaptionBody string `json:"caption_body"`
+}
+
+// FetchArchive 获取视频的原始数据用于归档：视频页面 HTML、Innertube player 响应、按 languages 选中的字幕 URL 和字幕原始响应体
+// （languages 为空时使用 WithDefaultLanguages 设置的语言）。与 Fetch 使用相同的请求步骤，但保留中间结果，便于之后离线重新提取、
+// 复现和排查提取逻辑的回归。只使用第一个 Innertube 客户端，不做客户端回退、嵌入式播放器回退和被阻止后的重试，也不读写 CaptionCache。
+// 某一步失败时返回错误，并返回已获取到的部分数据（视频页面请求失败时为 nil）。注意数据体积较大，参见 ArchiveBundle
+func (api *YouTubeTranscriptApi) FetchArchive(videoID string, languages []string) (*ArchiveBundle, error) {
+	return api.FetchArchiveContext(context.Background(), videoID, languages)
+}
+
+// FetchArchiveContext 与 FetchArchive 相同，ctx 取消时中止请求
+func (api *YouTubeTranscriptApi) FetchArchiveContext(ctx context.Context, videoID string, languages []string) (*ArchiveBundle, error) {
+	if len(languages) == 0 {
+		languages = api.options.languages()
+	}
+	return api.fetcher.fetchArchive(ctx, videoID, languages)
+}
+
+func (tlf *TranscriptListFetcher) fetchArchive(ctx context.Context, videoID string, languages []string) (*ArchiveBundle, error) {
+	if tlf.httpClient.isClosed() {
+		return nil, ErrClientClosed
+	}
+	ctx = withRequestVideoID(ctx, videoID)
+	tlf.stats = FetchStats{}
+	tlf.retryStart = time.Now()
+
+	html, apiKey, err := tlf.fetchWatchPage(ctx, videoID)
+	if err != nil {
+		return nil, err
+	}
+	client := tlf.requestClient(tlf.options.clients()[0], html)
+	bundle := &ArchiveBundle{
+		VideoID:         videoID,
+		FetchedAt:       time.Now(),
+		InnertubeClient: client.Name,
+		WatchHTML:       html,
+	}
+
+	innertubeData, body, err := tlf.fetchInnertubeResponse(ctx, videoID, apiKey, client)
+	if err != nil {
+		return bundle, err
+	}
+	bundle.InnertubeJSON = body
+
+	transcriptList, err := archivedTranscriptList(tlf.httpClient, videoID, innertubeData)
+	if err != nil {
+		return bundle, err
+	}
+	transcript, err := transcriptList.FindTranscript(languages)
+	if err != nil {
+		return bundle, err
+	}
+	bundle.CaptionURL = transcript.url
+	bundle.LanguageCode = transcript.LanguageCode
+	bundle.IsGenerated = transcript.IsGenerated
+	bundle.SourceLanguageCode = transcript.SourceLanguageCode
+
+	if strings.Contains(transcript.url, "&exp=xpe") {
+		return bundle, NewPoTokenRequired(videoID)
+	}
+	captionBody, err := fetchNonEmptyCaptionBody(ctx, tlf.httpClient, transcript.url, videoID, tlf.options.captionOptions().emptyBodyRetries)
+	if err != nil {
+		return bundle, err
+	}
+	bundle.CaptionBody = captionBody
+	return bundle, nil
+}
+
+// archivedTranscriptList 从 Innertube player 响应中构建字幕列表（检查可播放性的方式与 List 相同）
+func archivedTranscriptList(httpClient *HTTPClient, videoID string, innertubeData map[string]interface{}) (*TranscriptList, error) {
+	videoDetailsJSON, captionsJSON, err := (&TranscriptListFetcher{}).extractVideoDetailsAndCaptionsJSON(innertubeData, videoID)
+	if err != nil {
+		return nil, err
+	}
+	return BuildTranscriptList(httpClient, videoID, videoDetailsJSON, captionsJSON)
+}
+
+// Replay 不发送任何请求，使用归档的 Innertube 响应和字幕原始响应体重新提取字幕，得到与当时 Fetch 相同结构的结果
+// 使用当前版本的提取和解析逻辑，可用于验证提取逻辑的修改或复现问题；归档不完整（如 FetchArchive 中途失败）时返回相应的错误
+func (b *ArchiveBundle) Replay(preserveFormatting bool) (*FetchedTranscript, error) {
+	var innertubeData map[string]interface{}
+	if err := json.Unmarshal(b.InnertubeJSON, &innertubeData); err != nil {
+		return nil, NewYouTubeDataUnparsable(b.VideoID)
+	}
+
+	kind := TranscriptKindManual
+	if b.IsGenerated {
+		kind = TranscriptKindGenerated
+	}
+	transcriptList, err := archivedTranscriptList(nil, b.VideoID, innertubeData)
+	if err != nil {
+		return nil, err
+	}
+	var transcript *Transcript
+	if b.SourceLanguageCode != "" {
+		transcript, err = transcriptList.FindTranscriptStrict([]string{b.SourceLanguageCode}, kind)
+		if err == nil {
+			transcript, err = transcript.Translate(b.LanguageCode)
+		}
+	} else {
+		transcript, err = transcriptList.FindTranscriptStrict([]string{b.LanguageCode}, kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	snippets, err := NewTranscriptParser(preserveFormatting).Parse(b.CaptionBody)
+	if err := captionBodyError(b.CaptionBody, b.VideoID, err); err != nil {
+		return nil, err
+	}
+	return transcript.fetched(snippets), nil
+}
@@ -0,0 +1,251 @@
+package youtube_transcript_api
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// BatchResult 批量获取中单个视频的结果
+type BatchResult struct {
+	VideoID    string
+	Transcript *FetchedTranscript
+	Err        error
+}
+
+// ListBatchResult 批量获取字幕列表中单个视频的结果
+type ListBatchResult struct {
+	VideoID        string
+	TranscriptList *TranscriptList
+	Err            error
+}
+
+// FetchStreamBatch 并发获取多个视频的字幕，每完成一个就通过返回的 channel 发送结果（不保证顺序）
+// concurrency 为并发数（<= 0 时为 1），每个 worker 使用独立的 API 实例（相同的代理配置和选项，共享 api 的连接池，api.Close 会释放其中的连接）。
+// 重复的视频 ID 只获取一次，结果按出现次数重复发送（参见 WithBatchDuplicates）。
+// ctx 取消后不再开始新的视频，正在进行的请求会被中止（其结果的 Err 为 ctx.Err()），未开始的视频不会产生结果；所有 worker 退出后 channel 被关闭。
+// channel 的缓冲足以容纳全部结果，调用方中途停止读取不会使 worker 阻塞（剩余的视频仍会被获取，可取消 ctx 提前结束）
+func (api *YouTubeTranscriptApi) FetchStreamBatch(ctx context.Context, videoIDs []string, languages []string, preserveFormatting bool, concurrency int) <-chan BatchResult {
+	uniqueIDs, indexes := api.batchVideoIDs(videoIDs)
+	// 每个位置最多发送一次结果，按 len(indexes) 缓冲可保证发送不会永久阻塞
+	results := make(chan BatchResult, len(indexes))
+	occurrences := make([]int, len(uniqueIDs))
+	for _, index := range indexes {
+		occurrences[index]++
+	}
+
+	limits := api.newBatchLimits()
+
+	go func() {
+		defer close(results)
+		api.runBatch(ctx, uniqueIDs, concurrency, func(worker *YouTubeTranscriptApi, workerErr error, index int) {
+			result := BatchResult{VideoID: uniqueIDs[index], Err: workerErr}
+			if workerErr == nil {
+				result.Transcript, result.Err = limits.fetch(ctx, worker, uniqueIDs[index], languages, preserveFormatting)
+				result.Err = batchErr(ctx, result.Err)
+			}
+
+			for i := 0; i < occurrences[index]; i++ {
+				select {
+				case results <- result:
+				case <-ctx.Done():
+					return
+				}
+			}
+		})
+	}()
+
+	return results
+}
+
+// FetchBatch 并发获取多个视频的字幕，等待全部完成后按 videoIDs 的顺序返回结果
+// 重复的视频 ID 只获取一次，各个位置的结果共享同一个 *FetchedTranscript（参见 WithBatchDuplicates）。
+// ctx 取消后不再开始新的视频并等待正在进行的请求退出后返回：已完成的视频保留其结果，
+// 被中止和未开始的视频 Err 为 ctx.Err()
+func (api *YouTubeTranscriptApi) FetchBatch(ctx context.Context, videoIDs []string, languages []string, preserveFormatting bool, concurrency int) []BatchResult {
+	uniqueIDs, indexes := api.batchVideoIDs(videoIDs)
+	uniqueResults := make([]BatchResult, len(uniqueIDs))
+	started := make([]bool, len(uniqueIDs))
+	limits := api.newBatchLimits()
+
+	api.runBatch(ctx, uniqueIDs, concurrency, func(worker *YouTubeTranscriptApi, workerErr error, index int) {
+		started[index] = true
+		uniqueResults[index] = BatchResult{VideoID: uniqueIDs[index], Err: workerErr}
+		if workerErr == nil {
+			uniqueResults[index].Transcript, uniqueResults[index].Err = limits.fetch(ctx, worker, uniqueIDs[index], languages, preserveFormatting)
+			uniqueResults[index].Err = batchErr(ctx, uniqueResults[index].Err)
+		}
+	})
+
+	for i := range uniqueResults {
+		if !started[i] {
+			uniqueResults[i] = BatchResult{VideoID: uniqueIDs[i], Err: ctx.Err()}
+		}
+	}
+
+	results := make([]BatchResult, len(videoIDs))
+	for i, index := range indexes {
+		results[i] = uniqueResults[index]
+	}
+	return results
+}
+
+// ListBatch 并发获取多个视频的字幕列表，按 videoIDs 的顺序返回结果，去重和取消语义与 FetchBatch 相同
+func (api *YouTubeTranscriptApi) ListBatch(ctx context.Context, videoIDs []string, concurrency int) []ListBatchResult {
+	uniqueIDs, indexes := api.batchVideoIDs(videoIDs)
+	uniqueResults := make([]ListBatchResult, len(uniqueIDs))
+	started := make([]bool, len(uniqueIDs))
+
+	api.runBatch(ctx, uniqueIDs, concurrency, func(worker *YouTubeTranscriptApi, workerErr error, index int) {
+		started[index] = true
+		uniqueResults[index] = ListBatchResult{VideoID: uniqueIDs[index], Err: workerErr}
+		if workerErr == nil {
+			uniqueResults[index].TranscriptList, uniqueResults[index].Err = worker.ListContext(ctx, uniqueIDs[index])
+			uniqueResults[index].Err = batchErr(ctx, uniqueResults[index].Err)
+		}
+	})
+
+	for i := range uniqueResults {
+		if !started[i] {
+			uniqueResults[i] = ListBatchResult{VideoID: uniqueIDs[i], Err: ctx.Err()}
+		}
+	}
+
+	results := make([]ListBatchResult, len(videoIDs))
+	for i, index := range indexes {
+		results[i] = uniqueResults[index]
+	}
+	return results
+}
+
+// batchLimits 一次批量获取的 WithBatchLimits 上限和累计值，各 worker 通过原子操作共享
+type batchLimits struct {
+	maxSnippets int64
+	maxBytes    int64
+	snippets    int64
+	bytes       int64
+}
+
+// newBatchLimits 为一次批量获取创建计数器，未设置上限时返回 nil（不做任何检查）
+func (api *YouTubeTranscriptApi) newBatchLimits() *batchLimits {
+	if api.options.batchMaxSnippets <= 0 && api.options.batchMaxBytes <= 0 {
+		return nil
+	}
+	return &batchLimits{maxSnippets: api.options.batchMaxSnippets, maxBytes: api.options.batchMaxBytes}
+}
+
+// exceeded 返回已超过的上限类型（"snippets" 或 "bytes"），都未超过时返回空字符串
+func (l *batchLimits) exceeded() string {
+	if l.maxSnippets > 0 && atomic.LoadInt64(&l.snippets) > l.maxSnippets {
+		return "snippets"
+	}
+	if l.maxBytes > 0 && atomic.LoadInt64(&l.bytes) > l.maxBytes {
+		return "bytes"
+	}
+	return ""
+}
+
+// fetch 上限未超过时由 worker 获取字幕并累计片段数和字节数，否则返回 LimitExceeded
+func (l *batchLimits) fetch(ctx context.Context, worker *YouTubeTranscriptApi, videoID string, languages []string, preserveFormatting bool) (*FetchedTranscript, error) {
+	if l == nil {
+		return worker.FetchContext(ctx, videoID, languages, preserveFormatting)
+	}
+	if limit := l.exceeded(); limit != "" {
+		return nil, NewLimitExceeded(videoID, limit)
+	}
+
+	transcript, err := worker.FetchContext(ctx, videoID, languages, preserveFormatting)
+	if err != nil {
+		return nil, err
+	}
+	var bytes int64
+	for _, snippet := range transcript.Snippets {
+		bytes += int64(len(snippet.Text))
+	}
+	atomic.AddInt64(&l.snippets, int64(len(transcript.Snippets)))
+	atomic.AddInt64(&l.bytes, bytes)
+	return transcript, nil
+}
+
+// batchVideoIDs 返回批量任务实际要处理的视频 ID，以及 videoIDs 中每个位置对应的结果下标
+// 默认按首次出现的顺序去重；设置 WithBatchDuplicates 时原样返回
+func (api *YouTubeTranscriptApi) batchVideoIDs(videoIDs []string) ([]string, []int) {
+	if api.options.batchDuplicates {
+		indexes := make([]int, len(videoIDs))
+		for i := range indexes {
+			indexes[i] = i
+		}
+		return videoIDs, indexes
+	}
+	return dedupeVideoIDs(videoIDs)
+}
+
+// dedupeVideoIDs 按首次出现的顺序去除重复的视频 ID，indexes[i] 为 videoIDs[i] 在 unique 中的下标
+func dedupeVideoIDs(videoIDs []string) (unique []string, indexes []int) {
+	positions := make(map[string]int, len(videoIDs))
+	indexes = make([]int, len(videoIDs))
+	for i, videoID := range videoIDs {
+		position, ok := positions[videoID]
+		if !ok {
+			position = len(unique)
+			positions[videoID] = position
+			unique = append(unique, videoID)
+		}
+		indexes[i] = position
+	}
+	return unique, indexes
+}
+
+// batchErr 视频因 ctx 取消而失败时返回 ctx.Err()（而不是包装后的请求错误），便于调用方区分取消和其他错误
+func batchErr(ctx context.Context, err error) error {
+	if err != nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+// runBatch 使用 concurrency 个 worker（<= 0 时为 1）并发处理 videoIDs，每个 worker 使用独立的 API 实例（与 api 共享连接池，参见 clone）
+// 每个视频开始前都会检查 ctx，取消后不再分发和开始新的视频；所有 worker 和分发 goroutine 退出后才返回
+func (api *YouTubeTranscriptApi) runBatch(ctx context.Context, videoIDs []string, concurrency int, handle func(worker *YouTubeTranscriptApi, workerErr error, index int)) {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	if concurrency > len(videoIDs) {
+		concurrency = len(videoIDs)
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+
+	// 分发视频序号
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(jobs)
+		for i := range videoIDs {
+			select {
+			case jobs <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			worker, workerErr := api.clone()
+			for index := range jobs {
+				if ctx.Err() != nil {
+					// 排空 jobs，让分发 goroutine 尽快退出
+					continue
+				}
+				handle(worker, workerErr, index)
+			}
+		}()
+	}
+
+	wg.Wait()
+}
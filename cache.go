@@ -0,0 +1,39 @@
+package youtube_transcript_api
+
+import "sync"
+
+// CaptionCache 字幕响应体缓存，以字幕 URL 为键保存原始响应体
+// 缓存的是未解析的原始数据，因此同一条缓存可以用不同的 preserveFormatting 解析。
+// 实现需要是并发安全的，同一个缓存可以被多个 API 实例共享
+type CaptionCache interface {
+	Get(captionURL string) ([]byte, bool)
+	Set(captionURL string, body []byte)
+}
+
+// MemoryCaptionCache 基于内存的 CaptionCache 实现，没有容量限制和过期时间，适合开发调试
+type MemoryCaptionCache struct {
+	mu      sync.RWMutex
+	entries map[string][]byte
+}
+
+// NewMemoryCaptionCache 创建新的内存字幕缓存
+func NewMemoryCaptionCache() *MemoryCaptionCache {
+	return &MemoryCaptionCache{
+		entries: make(map[string][]byte),
+	}
+}
+
+// Get 返回缓存的响应体
+func (c *MemoryCaptionCache) Get(captionURL string) ([]byte, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	body, ok := c.entries[captionURL]
+	return body, ok
+}
+
+// Set 保存响应体
+func (c *MemoryCaptionCache) Set(captionURL string, body []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[captionURL] = append([]byte(nil), body...)
+}
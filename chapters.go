@@ -0,0 +1,93 @@
+package youtube_transcript_api
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Chapter 视频章节
+type Chapter struct {
+	Title string
+	// Start、End 章节的开始和结束时间（秒），最后一个章节在视频时长未知时 End 为 0，表示到视频结束
+	Start float64
+	End   float64
+}
+
+// chapterLinePattern 匹配视频简介中以时间戳开头的章节行，如 "0:00 Intro"、"1:02:03 - Results"
+var chapterLinePattern = regexp.MustCompile(`^\s*\(?((?:\d{1,2}:)?\d{1,2}:\d{2})\)?(?:\s*[-–—|]\s*|\s+)(.+?)\s*$`)
+
+// ParseChapters 从视频简介中解析章节，duration 为视频时长（秒，未知时传 0）
+// 规则与 YouTube 一致：时间戳按升序排列、第一个为 0:00，且至少有 3 个章节，否则认为视频没有章节并返回 nil
+func ParseChapters(description string, duration float64) []Chapter {
+	var chapters []Chapter
+	for _, line := range strings.Split(description, "\n") {
+		matches := chapterLinePattern.FindStringSubmatch(line)
+		if len(matches) != 3 {
+			continue
+		}
+		start, ok := parseChapterTimestamp(matches[1])
+		if !ok {
+			continue
+		}
+		if len(chapters) == 0 && start != 0 {
+			continue
+		}
+		if len(chapters) > 0 && start <= chapters[len(chapters)-1].Start {
+			continue
+		}
+		chapters = append(chapters, Chapter{Title: matches[2], Start: start})
+	}
+
+	if len(chapters) < 3 {
+		return nil
+	}
+	for i := range chapters {
+		if i < len(chapters)-1 {
+			chapters[i].End = chapters[i+1].Start
+		} else if duration > chapters[i].Start {
+			chapters[i].End = duration
+		}
+	}
+	return chapters
+}
+
+// parseChapterTimestamp 将 "M:SS"、"MM:SS" 或 "H:MM:SS" 解析为秒数
+func parseChapterTimestamp(timestamp string) (float64, bool) {
+	parts := strings.Split(timestamp, ":")
+	seconds := 0
+	for i, part := range parts {
+		value, err := strconv.Atoi(part)
+		if err != nil || (i > 0 && value >= 60) {
+			return 0, false
+		}
+		seconds = seconds*60 + value
+	}
+	return float64(seconds), true
+}
+
+// chaptersFromVideoDetails 从 videoDetails 的简介和时长中解析章节
+func chaptersFromVideoDetails(videoDetailsJSON map[string]interface{}) []Chapter {
+	description, _ := videoDetailsJSON["shortDescription"].(string)
+	lengthSeconds, _ := videoDetailsJSON["lengthSeconds"].(string)
+	duration, _ := strconv.ParseFloat(lengthSeconds, 64)
+	return ParseChapters(description, duration)
+}
+
+// Chapter 返回名称为 name（不区分大小写）的章节时间范围内的字幕片段
+// 章节来自视频简介中的时间戳（参见 ParseChapters），没有章节信息或没有匹配的章节时返回 false
+func (ft *FetchedTranscript) Chapter(name string) (*FetchedTranscript, bool) {
+	name = strings.TrimSpace(name)
+	for _, chapter := range ft.Chapters {
+		if !strings.EqualFold(chapter.Title, name) {
+			continue
+		}
+		end := chapter.End
+		if end == 0 {
+			end = math.Inf(1)
+		}
+		return ft.Slice(chapter.Start, end), true
+	}
+	return nil, false
+}
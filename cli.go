@@ -1,7 +1,16 @@
 package youtube_transcript_api
 
 import (
+	"archive/zip"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
 	"strings"
+	"unicode/utf16"
 )
 
 // CLIConfig 命令行配置
@@ -17,11 +26,43 @@ type CLIConfig struct {
 	WebshareProxyPassword  string
 	HTTPProxy              string
 	HTTPSProxy             string
+	// RetriesWhenBlocked 请求被阻止时的最大尝试次数，0 表示使用默认值（Webshare 为 WebshareDefaultRetriesWhenBlocked，通用代理不重试）
+	RetriesWhenBlocked int
+	// PostProcess 按顺序对获取到的字幕执行的内置处理函数名称，参见 TranscriptTransformNames
+	PostProcess []string
+	// SuppressErrors 为 true 时输出中只包含成功获取的数据，错误信息可通过 Errors 获取
+	SuppressErrors bool
+	// OutputZip 不为空时，将每个视频格式化后的字幕写入该 zip 文件中的 {videoID}.{ext} 条目，
+	// 错误信息写入 errors.txt 条目，Run 只返回写入结果的摘要。与 ListTranscripts 同时设置时 Run 返回错误（OutputDir、Gzip、Encoding 相同）
+	OutputZip string
+	// OutputDir 不为空时，将每个视频格式化后的字幕写入该目录下的 {videoID}.{ext} 文件（错误信息写入 errors.txt），
+	// Run 只返回写入结果的摘要
+	OutputDir string
+	// Grep 不为空时只保留文本匹配该正则表达式的字幕片段，打印时每行输出 "{videoID} [{时间}] {文本}"
+	Grep string
+	// Gzip 为 true 时 OutputDir 中写出的每个文件都使用 gzip 压缩并追加 ".gz" 后缀（如 {videoID}.srt.gz）；
+	// 只对 OutputDir 生效（zip 条目本身已经压缩），未设置 OutputDir 时 Run 返回错误
+	Gzip bool
+	// KeepDuplicates 为 true 时重复的视频 ID 各自获取和输出一次；默认只保留首次出现的 ID
+	KeepDuplicates bool
+	// Encoding 写入 OutputDir / OutputZip 的文件使用的编码，参见 OutputEncodingUTF8 等常量；
+	// 为空时使用不带 BOM 的 UTF-8。部分电视和老旧播放器要求 SRT 文件带 BOM 或使用 UTF-16；未设置 OutputDir / OutputZip 时 Run 返回错误
+	Encoding string
 }
 
+// CLI 文件输出支持的编码
+const (
+	OutputEncodingUTF8    = "utf-8"
+	OutputEncodingUTF8BOM = "utf-8-bom"
+	// OutputEncodingUTF16LE、OutputEncodingUTF16BE 总是带有对应字节序的 BOM
+	OutputEncodingUTF16LE = "utf-16le"
+	OutputEncodingUTF16BE = "utf-16be"
+)
+
 // YouTubeTranscriptCLI 命令行工具
 type YouTubeTranscriptCLI struct {
 	config CLIConfig
+	errors []error
 }
 
 // NewYouTubeTranscriptCLI 创建新的命令行工具实例
@@ -30,15 +71,18 @@ func NewYouTubeTranscriptCLI(config CLIConfig) *YouTubeTranscriptCLI {
 	for i, videoID := range config.VideoIDs {
 		config.VideoIDs[i] = strings.ReplaceAll(videoID, "\\", "")
 	}
+	if !config.KeepDuplicates {
+		config.VideoIDs, _ = dedupeVideoIDs(config.VideoIDs)
+	}
 
 	// 默认语言
 	if len(config.Languages) == 0 {
-		config.Languages = []string{"en"}
+		config.Languages = append([]string(nil), DefaultLanguages...)
 	}
 
 	// 默认格式
 	if config.Format == "" {
-		config.Format = "pretty"
+		config.Format = DefaultFormat
 	}
 
 	return &YouTubeTranscriptCLI{
@@ -57,26 +101,73 @@ func (cli *YouTubeTranscriptCLI) Run() (string, error) {
 	var err error
 
 	if cli.config.HTTPProxy != "" || cli.config.HTTPSProxy != "" {
-		proxyConfig, err = NewGenericProxyConfig(cli.config.HTTPProxy, cli.config.HTTPSProxy)
+		proxyConfig, err = NewGenericProxyConfigWithRetries(cli.config.HTTPProxy, cli.config.HTTPSProxy, cli.config.RetriesWhenBlocked)
 		if err != nil {
 			return "", err
 		}
 	}
 
 	if cli.config.WebshareProxyUsername != "" || cli.config.WebshareProxyPassword != "" {
+		retriesWhenBlocked := cli.config.RetriesWhenBlocked
+		if retriesWhenBlocked == 0 {
+			retriesWhenBlocked = WebshareDefaultRetriesWhenBlocked
+		}
 		proxyConfig, err = NewWebshareProxyConfig(
 			cli.config.WebshareProxyUsername,
 			cli.config.WebshareProxyPassword,
 			nil, // filterIPLocations
-			10,  // retriesWhenBlocked
-			"",  // domainName (使用默认值)
-			0,   // proxyPort (使用默认值)
+			retriesWhenBlocked,
+			"", // domainName (使用默认值)
+			0,  // proxyPort (使用默认值)
 		)
 		if err != nil {
 			return "", err
 		}
 	}
 
+	// 未通过参数指定代理时，使用环境变量中的代理
+	if proxyConfig == nil {
+		proxyConfig = ProxyConfigFromEnv()
+	}
+
+	pipeline, err := NewTranscriptPipelineFromNames(cli.config.PostProcess)
+	if err != nil {
+		return "", err
+	}
+
+	var grepPattern *regexp.Regexp
+	if cli.config.Grep != "" {
+		grepPattern, err = regexp.Compile(cli.config.Grep)
+		if err != nil {
+			return "", fmt.Errorf("invalid grep pattern: %w", err)
+		}
+	}
+
+	if cli.config.ListTranscripts && (cli.config.OutputZip != "" || cli.config.OutputDir != "" || cli.config.Gzip || cli.config.Encoding != "") {
+		return "", fmt.Errorf("output files (zip, directory, gzip, encoding) are not supported when listing transcripts")
+	}
+
+	if cli.config.Gzip && cli.config.OutputDir == "" {
+		return "", fmt.Errorf("gzip compression requires an output directory")
+	}
+
+	if cli.config.Encoding != "" && cli.config.OutputDir == "" && cli.config.OutputZip == "" {
+		return "", fmt.Errorf("an output encoding requires an output directory or zip file")
+	}
+
+	if _, err := encodeOutput("", cli.config.Encoding); err != nil {
+		return "", err
+	}
+
+	// 在请求之前校验输出格式
+	var formats []cliFormat
+	if !cli.config.ListTranscripts {
+		formats, err = loadCLIFormats(cli.config.Format)
+		if err != nil {
+			return "", err
+		}
+	}
+
 	// 创建 API 实例
 	api, err := NewYouTubeTranscriptApi(proxyConfig)
 	if err != nil {
@@ -103,39 +194,292 @@ func (cli *YouTubeTranscriptCLI) Run() (string, error) {
 				exceptions = append(exceptions, err)
 				continue
 			}
+			transcript = pipeline.Apply(transcript)
+			if grepPattern != nil {
+				transcript = transcript.Filter(func(snippet FetchedTranscriptSnippet) bool {
+					return grepPattern.MatchString(snippet.Text)
+				})
+			}
 			transcripts = append(transcripts, transcript)
 		}
 	}
 
+	if cli.config.OutputZip != "" {
+		cli.errors = exceptions
+		return cli.writeZip(transcripts, exceptions, formats)
+	}
+	if cli.config.OutputDir != "" {
+		cli.errors = exceptions
+		return cli.writeDir(transcripts, exceptions, formats)
+	}
+
 	// 构建输出
 	var outputSections []string
 
 	// 添加异常信息
-	for _, exception := range exceptions {
-		outputSections = append(outputSections, exception.Error())
+	cli.errors = exceptions
+	if !cli.config.SuppressErrors {
+		for _, exception := range exceptions {
+			outputSections = append(outputSections, exception.Error())
+		}
 	}
 
 	// 添加字幕数据
-	if cli.config.ListTranscripts {
+	if cli.config.ListTranscripts && listFormatIsJSON(cli.config.Format) {
+		formatted, err := formatTranscriptListsJSON(transcriptLists)
+		if err != nil {
+			return "", err
+		}
+		outputSections = append(outputSections, formatted)
+	} else if cli.config.ListTranscripts {
 		for _, transcriptList := range transcriptLists {
 			outputSections = append(outputSections, transcriptList.String())
 		}
+	} else if grepPattern != nil {
+		if matches := formatGrepMatches(transcripts); matches != "" {
+			outputSections = append(outputSections, matches)
+		}
 	} else if len(transcripts) > 0 {
-		formatterLoader := NewFormatterLoader()
-		formatter, err := formatterLoader.Load(cli.config.Format)
+		formatted, err := formatCLIOutputs(transcripts, formats)
 		if err != nil {
 			return "", err
 		}
+		outputSections = append(outputSections, formatted...)
+	}
 
-		formatted, err := formatter.FormatTranscripts(transcripts)
+	return strings.Join(outputSections, "\n\n"), nil
+}
+
+// formatCLIOutputs 将字幕按每种格式分别格式化，多种格式时每段前添加 "==> {format} <==" 标题
+func formatCLIOutputs(transcripts []*FetchedTranscript, formats []cliFormat) ([]string, error) {
+	var sections []string
+	for _, format := range formats {
+		formatted, err := format.formatter.FormatTranscripts(transcripts)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 
-		outputSections = append(outputSections, formatted)
+		// 多种格式时为每种格式添加标题
+		if len(formats) > 1 {
+			formatted = fmt.Sprintf("==> %s <==\n%s", format.name, formatted)
+		}
+		sections = append(sections, formatted)
 	}
+	return sections, nil
+}
 
-	return strings.Join(outputSections, "\n\n"), nil
+// listFormatIsJSON 判断列表模式是否输出 JSON：逗号分隔的格式中任意一个（解析别名后）为 json 时返回 true
+// 列表模式只区分 JSON 和文本输出，无法识别的格式名称按文本输出处理
+func listFormatIsJSON(formatList string) bool {
+	formatterLoader := NewFormatterLoader()
+	for _, formatType := range strings.Split(formatList, ",") {
+		if name, err := formatterLoader.resolve(formatType); err == nil && name == "json" {
+			return true
+		}
+	}
+	return false
+}
+
+// formatGrepMatches 将（已按 Grep 过滤的）字幕片段格式化为每行 "{videoID} [HH:MM:SS.mmm] {文本}"
+func formatGrepMatches(transcripts []*FetchedTranscript) string {
+	timestamps := &TextBasedFormatter{}
+
+	var lines []string
+	for _, transcript := range transcripts {
+		for _, snippet := range transcript.Snippets {
+			hours, mins, secs, ms := timestamps.secondsToTimestamp(snippet.Start)
+			text := strings.Join(strings.Fields(snippet.Text), " ")
+			lines = append(lines, fmt.Sprintf("%s [%02d:%02d:%02d.%03d] %s", transcript.VideoID, hours, mins, secs, ms, text))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// cliFormat 一种输出格式及其文件扩展名
+type cliFormat struct {
+	name      string
+	formatter Formatter
+	extension string
+}
+
+// loadCLIFormats 解析逗号分隔的格式列表，校验每个格式名称并去重
+// 多个格式的扩展名相同时（如 pretty 和 text 都是 txt），这些格式的扩展名改为 "{format}.{ext}" 以免文件名冲突
+func loadCLIFormats(formatList string) ([]cliFormat, error) {
+	formatterLoader := NewFormatterLoader()
+
+	var formats []cliFormat
+	seen := make(map[string]bool)
+	extensionCounts := make(map[string]int)
+	for _, formatType := range strings.Split(formatList, ",") {
+		if strings.TrimSpace(formatType) == "" {
+			continue
+		}
+		name, err := formatterLoader.resolve(formatType)
+		if err != nil {
+			return nil, err
+		}
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		formatter, err := formatterLoader.Load(name)
+		if err != nil {
+			return nil, err
+		}
+		extension, err := formatterLoader.Extension(name)
+		if err != nil {
+			return nil, err
+		}
+		formats = append(formats, cliFormat{name: name, formatter: formatter, extension: extension})
+		extensionCounts[extension]++
+	}
+
+	if len(formats) == 0 {
+		return nil, fmt.Errorf("no output format specified")
+	}
+
+	for i := range formats {
+		if extensionCounts[formats[i].extension] > 1 {
+			formats[i].extension = formats[i].name + "." + formats[i].extension
+		}
+	}
+	return formats, nil
+}
+
+// writeOutputs 将每个视频的每种格式以 {videoID}.{ext} 为名写出，错误信息写入 errors.txt，内容按 encoding 编码
+func writeOutputs(transcripts []*FetchedTranscript, exceptions []error, formats []cliFormat, encoding string, write func(name string, content []byte) error) error {
+	writeEncoded := func(name, content string) error {
+		encoded, err := encodeOutput(content, encoding)
+		if err != nil {
+			return err
+		}
+		return write(name, encoded)
+	}
+
+	for _, transcript := range transcripts {
+		for _, format := range formats {
+			formatted, err := format.formatter.FormatTranscript(transcript)
+			if err != nil {
+				return err
+			}
+			if err := writeEncoded(transcript.VideoID+"."+format.extension, formatted); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(exceptions) > 0 {
+		messages := make([]string, len(exceptions))
+		for i, exception := range exceptions {
+			messages[i] = exception.Error()
+		}
+		if err := writeEncoded("errors.txt", strings.Join(messages, "\n\n")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encodeOutput 将 content 按 encoding（参见 OutputEncodingUTF8 等常量，为空时为 UTF-8）编码，不支持的编码返回错误
+func encodeOutput(content, encoding string) ([]byte, error) {
+	switch strings.ToLower(encoding) {
+	case "", OutputEncodingUTF8:
+		return []byte(content), nil
+	case OutputEncodingUTF8BOM:
+		return append([]byte{0xEF, 0xBB, 0xBF}, content...), nil
+	case OutputEncodingUTF16LE, OutputEncodingUTF16BE:
+		var order binary.ByteOrder = binary.LittleEndian
+		if strings.EqualFold(encoding, OutputEncodingUTF16BE) {
+			order = binary.BigEndian
+		}
+		units := append([]uint16{0xFEFF}, utf16.Encode([]rune(content))...)
+		encoded := make([]byte, 2*len(units))
+		for i, unit := range units {
+			order.PutUint16(encoded[2*i:], unit)
+		}
+		return encoded, nil
+	}
+	return nil, fmt.Errorf("the encoding '%s' is not supported. Choose one of the following encodings: %s",
+		encoding, strings.Join([]string{OutputEncodingUTF8, OutputEncodingUTF8BOM, OutputEncodingUTF16LE, OutputEncodingUTF16BE}, ", "))
+}
+
+// writeZip 将字幕和错误信息写入 OutputZip 指定的 zip 文件
+func (cli *YouTubeTranscriptCLI) writeZip(transcripts []*FetchedTranscript, exceptions []error, formats []cliFormat) (string, error) {
+	file, err := os.Create(cli.config.OutputZip)
+	if err != nil {
+		return "", err
+	}
+
+	zipWriter := zip.NewWriter(file)
+	err = writeOutputs(transcripts, exceptions, formats, cli.config.Encoding, func(name string, content []byte) error {
+		return writeZipEntry(zipWriter, name, content)
+	})
+	if err == nil {
+		err = zipWriter.Close()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		// 不保留只写入了一部分的 zip 文件
+		os.Remove(cli.config.OutputZip)
+		return "", err
+	}
+
+	return fmt.Sprintf("Wrote %d transcript(s) and %d error(s) to %s", len(transcripts), len(exceptions), cli.config.OutputZip), nil
+}
+
+// writeDir 将字幕和错误信息写入 OutputDir 指定的目录（不存在时创建）
+func (cli *YouTubeTranscriptCLI) writeDir(transcripts []*FetchedTranscript, exceptions []error, formats []cliFormat) (string, error) {
+	if err := os.MkdirAll(cli.config.OutputDir, 0o755); err != nil {
+		return "", err
+	}
+
+	err := writeOutputs(transcripts, exceptions, formats, cli.config.Encoding, func(name string, content []byte) error {
+		path := filepath.Join(cli.config.OutputDir, name)
+		if cli.config.Gzip {
+			return writeGzipFile(path+".gz", content)
+		}
+		return os.WriteFile(path, content, 0o644)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("Wrote %d transcript(s) and %d error(s) to %s", len(transcripts), len(exceptions), cli.config.OutputDir), nil
+}
+
+// writeGzipFile 将 content 以 gzip 压缩写入 path
+func writeGzipFile(path string, content []byte) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	gzipWriter := gzip.NewWriter(file)
+	if _, err := gzipWriter.Write(content); err != nil {
+		return err
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+func writeZipEntry(zipWriter *zip.Writer, name string, content []byte) error {
+	entry, err := zipWriter.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = entry.Write(content)
+	return err
+}
+
+// Errors 返回最近一次 Run 中各个视频的错误（与是否设置 SuppressErrors 无关）
+func (cli *YouTubeTranscriptCLI) Errors() []error {
+	return cli.errors
 }
 
 func (cli *YouTubeTranscriptCLI) fetchTranscript(transcriptList *TranscriptList) (*FetchedTranscript, error) {
@@ -164,3 +508,15 @@ func (cli *YouTubeTranscriptCLI) fetchTranscript(transcriptList *TranscriptList)
 
 	return transcript.Fetch(false) // preserveFormatting = false
 }
+
+// formatTranscriptListsJSON 将字幕列表格式化为便于脚本处理的 JSON 数组（每个元素的结构参见 TranscriptList.MarshalJSON）
+func formatTranscriptListsJSON(transcriptLists []*TranscriptList) (string, error) {
+	if transcriptLists == nil {
+		transcriptLists = []*TranscriptList{}
+	}
+	jsonBytes, err := json.MarshalIndent(transcriptLists, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(jsonBytes), nil
+}
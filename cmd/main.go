@@ -12,15 +12,24 @@ import (
 func main() {
 	var (
 		listTranscripts        = flag.Bool("list-transcripts", false, "List the languages in which the given videos are available in")
-		languages              = flag.String("languages", "en", "A list of language codes in a descending priority (space-separated)")
+		languages              = flag.String("languages", "", "A list of language codes in a descending priority (space-separated, default: en)")
 		excludeGenerated       = flag.Bool("exclude-generated", false, "Exclude transcripts which have been generated by YouTube")
 		excludeManuallyCreated = flag.Bool("exclude-manually-created", false, "Exclude transcripts which have been manually created")
-		format                 = flag.String("format", "pretty", "Output format: json, pretty, text, webvtt, srt")
+		format                 = flag.String("format", "pretty", "Output format: json, pretty, text, webvtt, srt, csv (comma-separated for several, e.g. srt,json)")
 		translate              = flag.String("translate", "", "The language code for the language you want this transcript to be translated to")
 		webshareProxyUsername  = flag.String("webshare-proxy-username", "", "Webshare Proxy Username")
 		webshareProxyPassword  = flag.String("webshare-proxy-password", "", "Webshare Proxy Password")
 		httpProxy              = flag.String("http-proxy", "", "HTTP proxy URL")
 		httpsProxy             = flag.String("https-proxy", "", "HTTPS proxy URL")
+		postprocess            = flag.String("postprocess", "", "Comma-separated transforms applied in order: dedupe, merge, normalize, strip-music")
+		quiet                  = flag.Bool("quiet", false, "Only output successfully fetched data, omitting per-video error messages")
+		retriesWhenBlocked     = flag.Int("retries-when-blocked", 0, "Maximum attempts when YouTube blocks a request (0 = default: 10 for Webshare, no retries for --http-proxy/--https-proxy)")
+		outputZip              = flag.String("output-zip", "", "Write each video's transcript as {video_id}.{ext} into this zip file")
+		outputDir              = flag.String("output-dir", "", "Write each video's transcript as {video_id}.{ext} into this directory")
+		gzipOutput             = flag.Bool("gzip", false, "Gzip-compress the files written by --output-dir (appends .gz); requires --output-dir")
+		encoding               = flag.String("encoding", "", "Encoding of the files written by --output-dir/--output-zip: utf-8 (default), utf-8-bom, utf-16le, utf-16be")
+		keepDuplicates         = flag.Bool("keep-duplicates", false, "Fetch and output repeated video IDs once per occurrence instead of only once")
+		grep                   = flag.String("grep", "", "Only output snippets whose text matches this regular expression, prefixed with video ID and timestamp")
 		version                = flag.Bool("version", false, "Show version information")
 	)
 
@@ -30,6 +39,10 @@ func main() {
 		fmt.Fprintf(os.Stderr, "It also works for automatically generated subtitles and it does not require a headless browser.\n\n")
 		fmt.Fprintf(os.Stderr, "Options:\n")
 		flag.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nFormats:\n")
+		for _, info := range yt_transcript_api.NewFormatterLoader().FormatCatalog() {
+			fmt.Fprintf(os.Stderr, "  %-8s %-6s %s\n", info.Name, info.Extension, info.Description)
+		}
 	}
 
 	flag.Parse()
@@ -46,12 +59,17 @@ func main() {
 
 	videoIDs := flag.Args()
 
-	// 解析语言列表
-	languageList := []string{"en"}
+	// 解析语言列表（为空时由 CLI 使用默认语言）
+	var languageList []string
 	if *languages != "" {
 		languageList = strings.Fields(*languages)
 	}
 
+	var postprocessList []string
+	if *postprocess != "" {
+		postprocessList = strings.Split(*postprocess, ",")
+	}
+
 	config := yt_transcript_api.CLIConfig{
 		VideoIDs:               videoIDs,
 		ListTranscripts:        *listTranscripts,
@@ -64,6 +82,15 @@ func main() {
 		WebshareProxyPassword:  *webshareProxyPassword,
 		HTTPProxy:              *httpProxy,
 		HTTPSProxy:             *httpsProxy,
+		RetriesWhenBlocked:     *retriesWhenBlocked,
+		PostProcess:            postprocessList,
+		SuppressErrors:         *quiet,
+		OutputZip:              *outputZip,
+		OutputDir:              *outputDir,
+		Grep:                   *grep,
+		Gzip:                   *gzipOutput,
+		Encoding:               *encoding,
+		KeepDuplicates:         *keepDuplicates,
 	}
 
 	cli := yt_transcript_api.NewYouTubeTranscriptCLI(config)
@@ -95,12 +95,12 @@ func TestLanguageParsing(t *testing.T) {
 	}{
 		{"single language", "en", []string{"en"}},
 		{"multiple languages", "en zh de", []string{"en", "zh", "de"}},
-		{"empty string", "", []string{"en"}}, // 默认值
+		{"empty string", "", nil}, // 由 CLI 使用 DefaultLanguages
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			languageList := []string{"en"}
+			var languageList []string
 			if tc.input != "" {
 				languageList = strings.Fields(tc.input)
 			}
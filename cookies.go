@@ -0,0 +1,103 @@
+package youtube_transcript_api
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// jsonCookie 浏览器扩展导出的 JSON Cookie 条目（[{"name", "value", "domain", "path", "expirationDate", ...}, ...]）
+type jsonCookie struct {
+	Name           string   `json:"name"`
+	Value          string   `json:"value"`
+	Domain         string   `json:"domain"`
+	Path           string   `json:"path"`
+	ExpirationDate *float64 `json:"expirationDate"`
+	Secure         bool     `json:"secure"`
+	HTTPOnly       bool     `json:"httpOnly"`
+	HostOnly       bool     `json:"hostOnly"`
+}
+
+// NewYouTubeTranscriptApiWithCookiesJSON 与 NewYouTubeTranscriptApi 相同，并从 jsonPath 导入浏览器扩展导出的 JSON 格式 Cookie
+// （[{"name": ..., "value": ..., "domain": ..., "path": ..., "expirationDate": ...}, ...]，expirationDate 为 Unix 时间戳，
+// 缺失时视为会话 Cookie）。文件无法读取时返回 CookiePathInvalid；格式错误、条目缺少 name / domain
+// 或没有任何未过期的 Cookie 时返回 CookieInvalid。导入的 Cookie 在 Reset 后以及并发批量任务的各个 worker 中同样生效
+func NewYouTubeTranscriptApiWithCookiesJSON(proxyConfig ProxyConfig, jsonPath string, opts ...Option) (*YouTubeTranscriptApi, error) {
+	cookies, err := loadCookiesJSON(jsonPath)
+	if err != nil {
+		return nil, err
+	}
+	return NewYouTubeTranscriptApi(proxyConfig, append(append([]Option(nil), opts...), withCookies(cookies))...)
+}
+
+// importedCookie 从文件导入的 Cookie 及其所属的主机（host-only Cookie 的 Domain 为空，需要单独记录）
+type importedCookie struct {
+	host   string
+	cookie *http.Cookie
+}
+
+// withCookies 创建实例（以及 Reset）时将 cookies 加入 Cookie Jar
+func withCookies(cookies []importedCookie) Option {
+	return func(o *apiOptions) {
+		o.cookies = append(o.cookies, cookies...)
+	}
+}
+
+// loadCookiesJSON 读取并校验 JSON 格式的 Cookie 文件，跳过已过期的条目
+func loadCookiesJSON(path string) ([]importedCookie, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, NewCookiePathInvalid(path)
+	}
+
+	var entries []jsonCookie
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, NewCookieInvalid(path)
+	}
+
+	now := time.Now()
+	var cookies []importedCookie
+	for _, entry := range entries {
+		if entry.Name == "" || entry.Domain == "" {
+			return nil, NewCookieInvalid(path)
+		}
+		cookie := &http.Cookie{
+			Name:     entry.Name,
+			Value:    entry.Value,
+			Domain:   entry.Domain,
+			Path:     entry.Path,
+			Secure:   entry.Secure,
+			HttpOnly: entry.HTTPOnly,
+		}
+		if entry.HostOnly {
+			cookie.Domain = ""
+		}
+		if cookie.Path == "" {
+			cookie.Path = "/"
+		}
+		if entry.ExpirationDate != nil {
+			seconds, fraction := math.Modf(*entry.ExpirationDate)
+			cookie.Expires = time.Unix(int64(seconds), int64(fraction*float64(time.Second)))
+			if !cookie.Expires.After(now) {
+				continue
+			}
+		}
+		cookies = append(cookies, importedCookie{host: strings.TrimPrefix(entry.Domain, "."), cookie: cookie})
+	}
+
+	if len(cookies) == 0 {
+		return nil, NewCookieInvalid(path)
+	}
+	return cookies, nil
+}
+
+// setJarCookies 将导入的 Cookie 加入 jar，每个 Cookie 以其所属主机的 https URL 写入（jar 会忽略与主机不匹配的 Domain）
+func setJarCookies(jar http.CookieJar, cookies []importedCookie) {
+	for _, imported := range cookies {
+		jar.SetCookies(&url.URL{Scheme: "https", Host: imported.host, Path: "/"}, []*http.Cookie{imported.cookie})
+	}
+}
@@ -0,0 +1,112 @@
+package youtube_transcript_api
+
+import (
+	"context"
+)
+
+// DiagnosisStage 获取字幕列表过程中的一个步骤
+type DiagnosisStage string
+
+const (
+	DiagnosisStageHTML        DiagnosisStage = "html"
+	DiagnosisStageAPIKey      DiagnosisStage = "api_key"
+	DiagnosisStageInnertube   DiagnosisStage = "innertube"
+	DiagnosisStagePlayability DiagnosisStage = "playability"
+	DiagnosisStageCaptions    DiagnosisStage = "captions"
+)
+
+// DiagnosisReport Diagnose 的结果，记录获取字幕列表的每个步骤是否成功
+type DiagnosisReport struct {
+	VideoID string
+	// HTMLFetched 视频页面获取成功（包括需要时完成同意 Cookie 流程）
+	HTMLFetched bool
+	// ConsentCookieRequired 视频页面要求同意 Cookie
+	ConsentCookieRequired bool
+	// APIKeyExtracted 从视频页面中提取到了 INNERTUBE_API_KEY
+	APIKeyExtracted bool
+	// InnertubeOK Innertube player 请求成功
+	InnertubeOK bool
+	// Playable 视频可播放（playabilityStatus 为 OK 或缺失）
+	Playable bool
+	// CaptionsPresent player 响应中包含字幕轨道
+	CaptionsPresent bool
+	// ManualTrackCount、GeneratedTrackCount 手动创建和自动生成的字幕轨道数量
+	ManualTrackCount    int
+	GeneratedTrackCount int
+	// FailedStage 第一个失败的步骤，全部成功时为空
+	FailedStage DiagnosisStage
+	// Err FailedStage 步骤返回的错误（如 *IpBlocked、*TranscriptsDisabled），全部成功时为 nil
+	Err error
+}
+
+// Blocked 判断失败原因是否为请求被 YouTube 阻止（包括 IP 被封禁），而不是视频本身没有字幕
+func (r DiagnosisReport) Blocked() bool {
+	switch r.Err.(type) {
+	case *RequestBlocked, *IpBlocked:
+		return true
+	}
+	return false
+}
+
+// Diagnose 按步骤执行获取字幕列表的过程并报告每一步的结果，用于区分请求被阻止和视频确实没有字幕
+// 诊断不会重试，也不会做嵌入式播放器回退。报告总会返回；某一步失败时返回的 error 与 report.Err 相同
+func (api *YouTubeTranscriptApi) Diagnose(videoID string) (DiagnosisReport, error) {
+	return api.DiagnoseContext(context.Background(), videoID)
+}
+
+// DiagnoseContext 与 Diagnose 相同，ctx 取消时中止请求
+func (api *YouTubeTranscriptApi) DiagnoseContext(ctx context.Context, videoID string) (DiagnosisReport, error) {
+	report := api.fetcher.diagnose(ctx, videoID)
+	return report, report.Err
+}
+
+func (tlf *TranscriptListFetcher) diagnose(ctx context.Context, videoID string) DiagnosisReport {
+	ctx = withRequestVideoID(ctx, videoID)
+	tlf.stats = FetchStats{}
+	report := DiagnosisReport{VideoID: videoID}
+	fail := func(stage DiagnosisStage, err error) DiagnosisReport {
+		report.FailedStage, report.Err = stage, err
+		return report
+	}
+
+	html, err := tlf.fetchVideoHTML(ctx, videoID)
+	report.ConsentCookieRequired = tlf.stats.ConsentCookieRequired
+	if err != nil {
+		return fail(DiagnosisStageHTML, err)
+	}
+	report.HTMLFetched = true
+
+	apiKey, err := tlf.extractInnertubeAPIKey(html, videoID)
+	if err != nil {
+		return fail(DiagnosisStageAPIKey, err)
+	}
+	report.APIKeyExtracted = true
+
+	innertubeData, err := tlf.fetchInnertubeData(ctx, videoID, apiKey, tlf.requestClient(tlf.options.clients()[0], html))
+	if err != nil {
+		return fail(DiagnosisStageInnertube, err)
+	}
+	report.InnertubeOK = true
+
+	if err := tlf.assertPlayability(innertubeData, videoID); err != nil {
+		return fail(DiagnosisStagePlayability, err)
+	}
+	report.Playable = true
+
+	videoDetailsJSON, captionsJSON, err := tlf.extractVideoDetailsAndCaptionsJSON(innertubeData, videoID)
+	if err != nil {
+		return fail(DiagnosisStageCaptions, err)
+	}
+	transcriptList, err := BuildTranscriptList(tlf.httpClient, videoID, videoDetailsJSON, captionsJSON)
+	if err != nil {
+		return fail(DiagnosisStageCaptions, err)
+	}
+	report.ManualTrackCount = len(transcriptList.manuallyCreatedTranscripts)
+	report.GeneratedTrackCount = len(transcriptList.generatedTranscripts)
+	report.CaptionsPresent = report.ManualTrackCount+report.GeneratedTrackCount > 0
+	if !report.CaptionsPresent {
+		return fail(DiagnosisStageCaptions, NewTranscriptsDisabled(videoID))
+	}
+
+	return report
+}
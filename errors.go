@@ -1,6 +1,7 @@
 package youtube_transcript_api
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 )
@@ -14,11 +15,19 @@ func (e *YouTubeTranscriptApiException) Error() string {
 	return e.Message
 }
 
+func (e *YouTubeTranscriptApiException) Code() string {
+	return "YOUTUBE_TRANSCRIPT_API_ERROR"
+}
+
 // CookieError Cookie 相关错误
 type CookieError struct {
 	*YouTubeTranscriptApiException
 }
 
+func (e *CookieError) Code() string {
+	return "COOKIE_ERROR"
+}
+
 // CookiePathInvalid Cookie 路径无效
 type CookiePathInvalid struct {
 	*CookieError
@@ -36,6 +45,10 @@ func NewCookiePathInvalid(path string) *CookiePathInvalid {
 	}
 }
 
+func (e *CookiePathInvalid) Code() string {
+	return "COOKIE_PATH_INVALID"
+}
+
 // CookieInvalid Cookie 无效
 type CookieInvalid struct {
 	*CookieError
@@ -53,6 +66,10 @@ func NewCookieInvalid(path string) *CookieInvalid {
 	}
 }
 
+func (e *CookieInvalid) Code() string {
+	return "COOKIE_INVALID"
+}
+
 // CouldNotRetrieveTranscript 无法获取字幕的基类
 type CouldNotRetrieveTranscript struct {
 	*YouTubeTranscriptApiException
@@ -60,10 +77,15 @@ type CouldNotRetrieveTranscript struct {
 }
 
 func (e *CouldNotRetrieveTranscript) buildErrorMessage() string {
-	videoURL := fmt.Sprintf(WatchURLTemplate, e.VideoID)
+	return buildTranscriptErrorMessage(e.VideoID, e.Cause())
+}
+
+// buildTranscriptErrorMessage 生成视频 videoID 无法获取字幕的错误信息，cause 不为空时附加原因说明
+// 嵌入的 CouldNotRetrieveTranscript.Error 只能看到基类的 Cause，需要在 Error 中展示原因的子类型直接调用它
+func buildTranscriptErrorMessage(videoID, cause string) string {
+	videoURL := fmt.Sprintf(WatchURLTemplate, videoID)
 	errorMsg := fmt.Sprintf("\nCould not retrieve a transcript for the video %s!", videoURL)
 
-	cause := e.Cause()
 	if cause != "" {
 		errorMsg += fmt.Sprintf(" This is most likely caused by:\n\n%s", cause)
 		errorMsg += "\n\nIf you are sure that the described cause is not responsible for this error " +
@@ -85,6 +107,12 @@ func (e *CouldNotRetrieveTranscript) Error() string {
 	return e.buildErrorMessage()
 }
 
+// Code 返回稳定的机器可读错误码（如 "TRANSCRIPTS_DISABLED"、"REQUEST_BLOCKED"），用于日志和结构化的错误响应；
+// 每个子类型返回自己的错误码，错误码不会随 Error() 的文本变化
+func (e *CouldNotRetrieveTranscript) Code() string {
+	return "COULD_NOT_RETRIEVE_TRANSCRIPT"
+}
+
 // YouTubeDataUnparsable YouTube 数据无法解析
 type YouTubeDataUnparsable struct {
 	*CouldNotRetrieveTranscript
@@ -104,10 +132,16 @@ func (e *YouTubeDataUnparsable) Cause() string {
 		"not happen, please open an issue (make sure to include the video ID)!"
 }
 
+func (e *YouTubeDataUnparsable) Code() string {
+	return "YOUTUBE_DATA_UNPARSABLE"
+}
+
 // YouTubeRequestFailed YouTube 请求失败
 type YouTubeRequestFailed struct {
 	*CouldNotRetrieveTranscript
 	Reason string
+	// StatusCode 响应的 HTTP 状态码，请求未得到响应时为 0
+	StatusCode int
 }
 
 func NewYouTubeRequestFailed(videoID string, err error) *YouTubeRequestFailed {
@@ -124,11 +158,19 @@ func (e *YouTubeRequestFailed) Cause() string {
 	return fmt.Sprintf("Request to YouTube failed: %s", e.Reason)
 }
 
+func (e *YouTubeRequestFailed) Code() string {
+	return "YOUTUBE_REQUEST_FAILED"
+}
+
 // VideoUnplayable 视频无法播放
 type VideoUnplayable struct {
 	*CouldNotRetrieveTranscript
 	Reason     string
 	SubReasons []string
+	// Status playabilityStatus.status 的原始值（如 "ERROR"、"UNPLAYABLE"、"LOGIN_REQUIRED"）
+	Status string
+	// ErrorScreen playabilityStatus.errorScreen 的原始数据，便于识别尚未专门处理的情况（可能为 nil）
+	ErrorScreen map[string]interface{}
 }
 
 func NewVideoUnplayable(videoID string, reason string, subReasons []string) *VideoUnplayable {
@@ -159,6 +201,10 @@ func (e *VideoUnplayable) Cause() string {
 	return fmt.Sprintf("The video is unplayable for the following reason: %s", reason)
 }
 
+func (e *VideoUnplayable) Code() string {
+	return "VIDEO_UNPLAYABLE"
+}
+
 // VideoUnavailable 视频不可用
 type VideoUnavailable struct {
 	*CouldNotRetrieveTranscript
@@ -177,6 +223,148 @@ func (e *VideoUnavailable) Cause() string {
 	return "The video is no longer available"
 }
 
+func (e *VideoUnavailable) Code() string {
+	return "VIDEO_UNAVAILABLE"
+}
+
+// PlaylistUnavailable 播放列表不存在、是私享的或页面中没有视频列表，VideoID 为播放列表 ID
+type PlaylistUnavailable struct {
+	*CouldNotRetrieveTranscript
+	// Reason YouTube 页面上显示的提示信息（如 "The playlist does not exist."），没有时为空
+	Reason string
+}
+
+func NewPlaylistUnavailable(playlistID, reason string) *PlaylistUnavailable {
+	return &PlaylistUnavailable{
+		CouldNotRetrieveTranscript: &CouldNotRetrieveTranscript{
+			YouTubeTranscriptApiException: &YouTubeTranscriptApiException{},
+			VideoID:                       playlistID,
+		},
+		Reason: reason,
+	}
+}
+
+func (e *PlaylistUnavailable) Cause() string {
+	if e.Reason != "" {
+		return fmt.Sprintf("The playlist is unavailable: %s", e.Reason)
+	}
+	return "The playlist is unavailable (it may be private or may not exist)"
+}
+
+func (e *PlaylistUnavailable) Error() string {
+	return buildPlaylistErrorMessage(e.VideoID, e.Cause())
+}
+
+func (e *PlaylistUnavailable) Code() string {
+	return "PLAYLIST_UNAVAILABLE"
+}
+
+// PlaylistRequestFailed 请求播放列表页面或续页接口失败，VideoID 为播放列表 ID
+type PlaylistRequestFailed struct {
+	*CouldNotRetrieveTranscript
+	Reason string
+	// StatusCode 响应的 HTTP 状态码，请求未得到响应时为 0
+	StatusCode int
+}
+
+func NewPlaylistRequestFailed(playlistID string, err error) *PlaylistRequestFailed {
+	return &PlaylistRequestFailed{
+		CouldNotRetrieveTranscript: &CouldNotRetrieveTranscript{
+			YouTubeTranscriptApiException: &YouTubeTranscriptApiException{},
+			VideoID:                       playlistID,
+		},
+		Reason: err.Error(),
+	}
+}
+
+func (e *PlaylistRequestFailed) Cause() string {
+	return fmt.Sprintf("Request to YouTube failed: %s", e.Reason)
+}
+
+func (e *PlaylistRequestFailed) Error() string {
+	return buildPlaylistErrorMessage(e.VideoID, e.Cause())
+}
+
+func (e *PlaylistRequestFailed) Code() string {
+	return "PLAYLIST_REQUEST_FAILED"
+}
+
+// PlaylistTruncated 播放列表超过 maxPlaylistPages 页，只获取了前面的部分视频，VideoID 为播放列表 ID
+// ListPlaylistVideoIDs 返回该错误时同时返回已获取的视频 ID
+type PlaylistTruncated struct {
+	*CouldNotRetrieveTranscript
+	// VideoCount 已获取的视频数
+	VideoCount int
+}
+
+func NewPlaylistTruncated(playlistID string, videoCount int) *PlaylistTruncated {
+	return &PlaylistTruncated{
+		CouldNotRetrieveTranscript: &CouldNotRetrieveTranscript{
+			YouTubeTranscriptApiException: &YouTubeTranscriptApiException{},
+			VideoID:                       playlistID,
+		},
+		VideoCount: videoCount,
+	}
+}
+
+func (e *PlaylistTruncated) Cause() string {
+	return fmt.Sprintf("The playlist has more than %d pages, only the first %d videos were retrieved", maxPlaylistPages, e.VideoCount)
+}
+
+func (e *PlaylistTruncated) Error() string {
+	return buildPlaylistErrorMessage(e.VideoID, e.Cause())
+}
+
+func (e *PlaylistTruncated) Code() string {
+	return "PLAYLIST_TRUNCATED"
+}
+
+// buildPlaylistErrorMessage 生成播放列表错误的消息（指向播放列表 URL 而不是视频 URL）
+func buildPlaylistErrorMessage(playlistID, cause string) string {
+	playlistURL := fmt.Sprintf(PlaylistURLTemplate, playlistID)
+	return fmt.Sprintf("\nCould not retrieve the playlist %s! This is most likely caused by:\n\n%s", playlistURL, cause)
+}
+
+// asPlaylistError 将使用播放列表 ID 创建的 YouTubeRequestFailed 转换为 PlaylistRequestFailed，其他错误原样返回
+func asPlaylistError(err error) error {
+	requestFailed, ok := err.(*YouTubeRequestFailed)
+	if !ok {
+		return err
+	}
+	playlistErr := NewPlaylistRequestFailed(requestFailed.VideoID, errors.New(requestFailed.Reason))
+	playlistErr.StatusCode = requestFailed.StatusCode
+	return playlistErr
+}
+
+// LimitExceeded 批量任务的累计片段数或字节数已超过 WithBatchLimits 设置的上限，该视频没有被获取
+type LimitExceeded struct {
+	*CouldNotRetrieveTranscript
+	// Limit 超过的上限类型："snippets" 或 "bytes"
+	Limit string
+}
+
+func NewLimitExceeded(videoID, limit string) *LimitExceeded {
+	return &LimitExceeded{
+		CouldNotRetrieveTranscript: &CouldNotRetrieveTranscript{
+			YouTubeTranscriptApiException: &YouTubeTranscriptApiException{},
+			VideoID:                       videoID,
+		},
+		Limit: limit,
+	}
+}
+
+func (e *LimitExceeded) Cause() string {
+	return fmt.Sprintf("The batch stopped before this video because the total number of %s fetched exceeded the configured WithBatchLimits %s limit", e.Limit, e.Limit)
+}
+
+func (e *LimitExceeded) Error() string {
+	return buildTranscriptErrorMessage(e.VideoID, e.Cause())
+}
+
+func (e *LimitExceeded) Code() string {
+	return "LIMIT_EXCEEDED"
+}
+
 // InvalidVideoId 无效的视频 ID
 type InvalidVideoId struct {
 	*CouldNotRetrieveTranscript
@@ -197,6 +385,10 @@ func (e *InvalidVideoId) Cause() string {
 		"Instead run: `YouTubeTranscriptApi().fetch(\"1234\")`"
 }
 
+func (e *InvalidVideoId) Code() string {
+	return "INVALID_VIDEO_ID"
+}
+
 // RequestBlocked 请求被阻止（IP 封禁）
 type RequestBlocked struct {
 	*CouldNotRetrieveTranscript
@@ -269,6 +461,10 @@ func (e *RequestBlocked) Cause() string {
 		"with! So only do this if you don't mind your account being banned!"
 }
 
+func (e *RequestBlocked) Code() string {
+	return "REQUEST_BLOCKED"
+}
+
 // IpBlocked IP 被封禁
 type IpBlocked struct {
 	*RequestBlocked
@@ -293,6 +489,10 @@ func (e *IpBlocked) Cause() string {
 		"#working-around-ip-bans-requestblocked-or-ipblocked-exception).\n"
 }
 
+func (e *IpBlocked) Code() string {
+	return "IP_BLOCKED"
+}
+
 // TranscriptsDisabled 字幕已禁用
 type TranscriptsDisabled struct {
 	*CouldNotRetrieveTranscript
@@ -311,6 +511,10 @@ func (e *TranscriptsDisabled) Cause() string {
 	return "Subtitles are disabled for this video"
 }
 
+func (e *TranscriptsDisabled) Code() string {
+	return "TRANSCRIPTS_DISABLED"
+}
+
 // AgeRestricted 年龄限制视频
 type AgeRestricted struct {
 	*CouldNotRetrieveTranscript
@@ -333,6 +537,10 @@ func (e *AgeRestricted) Cause() string {
 		"implementation. I will do my best to re-implement it as soon as possible."
 }
 
+func (e *AgeRestricted) Code() string {
+	return "AGE_RESTRICTED"
+}
+
 // NotTranslatable 不可翻译
 type NotTranslatable struct {
 	*CouldNotRetrieveTranscript
@@ -351,6 +559,10 @@ func (e *NotTranslatable) Cause() string {
 	return "The requested language is not translatable"
 }
 
+func (e *NotTranslatable) Code() string {
+	return "NOT_TRANSLATABLE"
+}
+
 // TranslationLanguageNotAvailable 翻译语言不可用
 type TranslationLanguageNotAvailable struct {
 	*CouldNotRetrieveTranscript
@@ -369,6 +581,10 @@ func (e *TranslationLanguageNotAvailable) Cause() string {
 	return "The requested translation language is not available"
 }
 
+func (e *TranslationLanguageNotAvailable) Code() string {
+	return "TRANSLATION_LANGUAGE_NOT_AVAILABLE"
+}
+
 // FailedToCreateConsentCookie 创建同意 Cookie 失败
 type FailedToCreateConsentCookie struct {
 	*CouldNotRetrieveTranscript
@@ -387,11 +603,17 @@ func (e *FailedToCreateConsentCookie) Cause() string {
 	return "Failed to automatically give consent to saving cookies"
 }
 
+func (e *FailedToCreateConsentCookie) Code() string {
+	return "FAILED_TO_CREATE_CONSENT_COOKIE"
+}
+
 // NoTranscriptFound 未找到字幕
 type NoTranscriptFound struct {
 	*CouldNotRetrieveTranscript
 	RequestedLanguageCodes []string
 	TranscriptData         *TranscriptList
+	// TranslatableTo 请求的语言中虽然没有字幕轨道、但可以通过 Transcript.Translate 翻译得到的语言代码
+	TranslatableTo []string
 }
 
 func NewNoTranscriptFound(videoID string, requestedLanguageCodes []string, transcriptData *TranscriptList) *NoTranscriptFound {
@@ -406,8 +628,23 @@ func NewNoTranscriptFound(videoID string, requestedLanguageCodes []string, trans
 }
 
 func (e *NoTranscriptFound) Cause() string {
-	return fmt.Sprintf("No transcripts were found for any of the requested language codes: %v\n\n%s",
-		e.RequestedLanguageCodes, e.TranscriptData.String())
+	cause := fmt.Sprintf("No transcripts were found for any of the requested language codes: %v", e.RequestedLanguageCodes)
+	if e.TranscriptData != nil {
+		cause += "\n\n" + e.TranscriptData.String()
+	}
+	if len(e.TranslatableTo) > 0 {
+		cause += fmt.Sprintf("\n\nThese requested languages are available as translations, use Transcript.Translate: %v",
+			e.TranslatableTo)
+	}
+	return cause
+}
+
+func (e *NoTranscriptFound) Error() string {
+	return buildTranscriptErrorMessage(e.VideoID, e.Cause())
+}
+
+func (e *NoTranscriptFound) Code() string {
+	return "NO_TRANSCRIPT_FOUND"
 }
 
 // PoTokenRequired 需要 PO Token
@@ -429,13 +666,32 @@ func (e *PoTokenRequired) Cause() string {
 		"please open a GitHub issue!"
 }
 
+func (e *PoTokenRequired) Code() string {
+	return "PO_TOKEN_REQUIRED"
+}
+
+// ErrorCode 返回 err（或其包装链中第一个带错误码的错误）的机器可读错误码，参见 CouldNotRetrieveTranscript.Code；
+// err 为 nil 时返回空字符串，不是本库错误时返回 "UNKNOWN"
+func ErrorCode(err error) string {
+	if err == nil {
+		return ""
+	}
+	var coded interface{ Code() string }
+	if errors.As(err, &coded) {
+		return coded.Code()
+	}
+	return "UNKNOWN"
+}
+
 // raiseHTTPErrors 检查 HTTP 响应并抛出相应的错误
 func raiseHTTPErrors(resp *http.Response, videoID string) error {
 	if resp.StatusCode == http.StatusTooManyRequests {
 		return NewIpBlocked(videoID)
 	}
 	if resp.StatusCode >= 400 {
-		return NewYouTubeRequestFailed(videoID, fmt.Errorf("HTTP %d: %s", resp.StatusCode, resp.Status))
+		requestFailed := NewYouTubeRequestFailed(videoID, fmt.Errorf("HTTP %d: %s", resp.StatusCode, resp.Status))
+		requestFailed.StatusCode = resp.StatusCode
+		return requestFailed
 	}
 	return nil
 }
@@ -0,0 +1,368 @@
+package youtube_transcript_api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Equal 比较两个字幕的元数据和字幕片段是否一致
+// 片段文本需完全相同，开始时间和持续时间允许 timeTolerance（秒）以内的误差，避免浮点比较不稳定
+func (ft *FetchedTranscript) Equal(other *FetchedTranscript, timeTolerance float64) bool {
+	if ft == nil || other == nil {
+		return ft == other
+	}
+
+	if ft.Title != other.Title ||
+		ft.ThumbnailURL != other.ThumbnailURL ||
+		ft.VideoID != other.VideoID ||
+		ft.Language != other.Language ||
+		ft.LanguageCode != other.LanguageCode ||
+		ft.IsGenerated != other.IsGenerated ||
+		ft.SourceLanguageCode != other.SourceLanguageCode {
+		return false
+	}
+
+	if len(ft.Snippets) != len(other.Snippets) {
+		return false
+	}
+	for i, snippet := range ft.Snippets {
+		if !snippet.Equal(other.Snippets[i], timeTolerance) {
+			return false
+		}
+	}
+	return true
+}
+
+// Equal 比较两个字幕片段，时间允许 timeTolerance（秒）以内的误差
+func (s FetchedTranscriptSnippet) Equal(other FetchedTranscriptSnippet, timeTolerance float64) bool {
+	return s.Text == other.Text &&
+		math.Abs(s.Start-other.Start) <= timeTolerance &&
+		math.Abs(s.Duration-other.Duration) <= timeTolerance
+}
+
+// copyWithSnippets 复制字幕元数据，并使用给定的字幕片段
+func (ft *FetchedTranscript) copyWithSnippets(snippets []FetchedTranscriptSnippet) *FetchedTranscript {
+	result := *ft
+	result.Snippets = snippets
+	return &result
+}
+
+// Filter 返回只包含 keep 返回 true 的字幕片段的新字幕，元数据保持不变，原字幕不会被修改
+func (ft *FetchedTranscript) Filter(keep func(FetchedTranscriptSnippet) bool) *FetchedTranscript {
+	var snippets []FetchedTranscriptSnippet
+	for _, snippet := range ft.Snippets {
+		if keep(snippet) {
+			snippets = append(snippets, snippet)
+		}
+	}
+	return ft.copyWithSnippets(snippets)
+}
+
+// Deduplicate 合并自动生成的"滚动"字幕中的重复内容
+// 滚动字幕中每个新片段都会重复上一个片段的（部分）文本再追加新词，
+// 该方法去掉与上一个片段重叠的前缀，只保留新增文本；完全重复的片段会被合并到上一个片段中（延长其持续时间）。
+// 返回新的 FetchedTranscript，原字幕不会被修改
+func (ft *FetchedTranscript) Deduplicate() *FetchedTranscript {
+	var snippets []FetchedTranscriptSnippet
+	var previousWords []string
+
+	for _, snippet := range ft.Snippets {
+		words := strings.Fields(snippet.Text)
+		overlap := rollingOverlap(previousWords, words)
+		previousWords = words
+
+		if overlap == 0 {
+			snippets = append(snippets, snippet)
+			continue
+		}
+
+		if overlap == len(words) {
+			// 没有新增内容：延长上一个片段以覆盖当前片段
+			if len(snippets) > 0 {
+				last := &snippets[len(snippets)-1]
+				if end := snippet.Start + snippet.Duration; end > last.Start+last.Duration {
+					last.Duration = end - last.Start
+				}
+			}
+			continue
+		}
+
+		snippet.Text = strings.Join(words[overlap:], " ")
+		snippets = append(snippets, snippet)
+	}
+
+	return ft.copyWithSnippets(snippets)
+}
+
+// rollingOverlap 返回 previous 的结尾与 current 的开头重叠的词数
+// 为避免误删常见词，只有在重叠覆盖整个上一个片段、整个当前片段或至少两个词时才认为是滚动重复
+func rollingOverlap(previous, current []string) int {
+	maxOverlap := len(previous)
+	if len(current) < maxOverlap {
+		maxOverlap = len(current)
+	}
+
+	for k := maxOverlap; k > 0; k-- {
+		if !wordsEqual(previous[len(previous)-k:], current[:k]) {
+			continue
+		}
+		if k == len(previous) || k == len(current) || k >= 2 {
+			return k
+		}
+		return 0
+	}
+	return 0
+}
+
+func wordsEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// formattingTagPattern 匹配任意一个允许保留的格式标签
+var formattingTagPattern = regexp.MustCompile(`(?i)</?(?:` + strings.Join(formattingTags, "|") + `)\b[^>]*>`)
+
+// HasFormatting 检查是否有字幕片段包含格式标签（如 <i>、<b>）
+// 仅在以 preserveFormatting=true 获取字幕时才可能返回 true，可用于决定按 HTML 还是纯文本渲染
+func (ft *FetchedTranscript) HasFormatting() bool {
+	for _, snippet := range ft.Snippets {
+		if formattingTagPattern.MatchString(snippet.Text) {
+			return true
+		}
+	}
+	return false
+}
+
+// Head 返回只包含前 n 个字幕片段的新字幕，元数据保持不变
+// n <= 0 时返回空字幕，n 大于片段数时返回全部片段
+func (ft *FetchedTranscript) Head(n int) *FetchedTranscript {
+	if n > len(ft.Snippets) {
+		n = len(ft.Snippets)
+	}
+	if n < 0 {
+		n = 0
+	}
+	return ft.copyWithSnippets(append([]FetchedTranscriptSnippet(nil), ft.Snippets[:n]...))
+}
+
+// Tail 返回只包含最后 n 个字幕片段的新字幕，边界行为与 Head 相同
+func (ft *FetchedTranscript) Tail(n int) *FetchedTranscript {
+	if n > len(ft.Snippets) {
+		n = len(ft.Snippets)
+	}
+	if n < 0 {
+		n = 0
+	}
+	return ft.copyWithSnippets(append([]FetchedTranscriptSnippet(nil), ft.Snippets[len(ft.Snippets)-n:]...))
+}
+
+// WordCount 返回所有字幕片段的词数（按空白分词）
+func (ft *FetchedTranscript) WordCount() int {
+	count := 0
+	for _, snippet := range ft.Snippets {
+		count += len(strings.Fields(snippet.Text))
+	}
+	return count
+}
+
+// DefaultWordsPerMinute ReadingTime 在未指定阅读速度时使用的每分钟词数
+const DefaultWordsPerMinute = 200
+
+// ReadingTime 按每分钟 wordsPerMinute 个词估算阅读全部字幕文本所需的时间，wordsPerMinute <= 0 时使用 DefaultWordsPerMinute
+func (ft *FetchedTranscript) ReadingTime(wordsPerMinute int) time.Duration {
+	if wordsPerMinute <= 0 {
+		wordsPerMinute = DefaultWordsPerMinute
+	}
+	return time.Duration(float64(ft.WordCount()) / float64(wordsPerMinute) * float64(time.Minute))
+}
+
+// SpeakingDuration 返回所有片段持续时间之和
+// 与字幕总跨度（第一个片段开始到最后一个片段结束）不同：片段之间的静默间隔不计入，
+// 而自动生成字幕中相互重叠的片段会被重复计算，因此结果可能大于也可能小于总跨度
+func (ft *FetchedTranscript) SpeakingDuration() time.Duration {
+	var total float64
+	for _, snippet := range ft.Snippets {
+		total += snippet.Duration
+	}
+	return time.Duration(total * float64(time.Second))
+}
+
+// Validate 检查字幕数据是否完整，返回所有发现的问题（没有问题时返回空）
+// 检查项与集成测试一致：文本不能为空、开始时间不能为负、持续时间必须大于 0，且片段按开始时间非递减排列
+func (ft *FetchedTranscript) Validate() []error {
+	var problems []error
+	for i, snippet := range ft.Snippets {
+		if strings.TrimSpace(snippet.Text) == "" {
+			problems = append(problems, fmt.Errorf("snippet %d: empty text", i))
+		}
+		if snippet.Start < 0 {
+			problems = append(problems, fmt.Errorf("snippet %d: negative start %f", i, snippet.Start))
+		}
+		if snippet.Duration <= 0 {
+			problems = append(problems, fmt.Errorf("snippet %d: non-positive duration %f", i, snippet.Duration))
+		}
+		if i > 0 && snippet.Start < ft.Snippets[i-1].Start {
+			problems = append(problems, fmt.Errorf("snippet %d: start %f is before previous start %f", i, snippet.Start, ft.Snippets[i-1].Start))
+		}
+	}
+	return problems
+}
+
+// WithComputedDurations 返回新的字幕，每个片段的持续时间改为到下一个片段开始的间隔，得到互不重叠的时间
+// 最后一个片段以及与下一个片段开始时间相同或更晚的片段保留原始持续时间；原字幕不会被修改，可继续使用 YouTube 提供的原始值
+func (ft *FetchedTranscript) WithComputedDurations() *FetchedTranscript {
+	snippets := append([]FetchedTranscriptSnippet(nil), ft.Snippets...)
+	for i := 0; i < len(snippets)-1; i++ {
+		if gap := snippets[i+1].Start - snippets[i].Start; gap > 0 {
+			snippets[i].Duration = gap
+		}
+	}
+	return ft.copyWithSnippets(snippets)
+}
+
+// SortByStart 返回按开始时间排序的新字幕，开始时间相同的片段保持原顺序（稳定排序），原字幕不会被修改
+// SnippetAt 以及格式化器都假定片段按开始时间排列，处理来源顺序不可靠的字幕时可以先调用该方法
+func (ft *FetchedTranscript) SortByStart() *FetchedTranscript {
+	return ft.SortBy(func(a, b FetchedTranscriptSnippet) bool {
+		return a.Start < b.Start
+	})
+}
+
+// SortBy 返回按 less 排序的新字幕（稳定排序，less 相等的片段保持原顺序），原字幕不会被修改
+// 用于分析场景下的任意排序，如倒序或按文本长度排序。格式化器（SRT、WebVTT 等）和 SnippetAt 都假定片段按时间顺序排列，
+// 导出字幕文件前应使用原字幕或 SortByStart 的结果
+func (ft *FetchedTranscript) SortBy(less func(a, b FetchedTranscriptSnippet) bool) *FetchedTranscript {
+	snippets := append([]FetchedTranscriptSnippet(nil), ft.Snippets...)
+	sort.SliceStable(snippets, func(i, j int) bool {
+		return less(snippets[i], snippets[j])
+	})
+	return ft.copyWithSnippets(snippets)
+}
+
+// Slice 返回与时间范围 [start, end)（秒）有重叠的片段组成的新字幕，片段保持原顺序（包括开始时间相同的片段）
+func (ft *FetchedTranscript) Slice(start, end float64) *FetchedTranscript {
+	return ft.Filter(func(snippet FetchedTranscriptSnippet) bool {
+		return snippet.Start < end && snippet.Start+snippet.Duration > start
+	})
+}
+
+// SnippetAt 返回在 t（秒）时正在显示的片段的下标：即开始时间不晚于 t 的最后一组片段中第一个仍覆盖 t 的片段，没有时返回 false
+// 多个片段开始时间相同时取原下标最小的（仍覆盖 t 的）片段，结果是确定的。
+// 使用二分查找，要求片段按开始时间非递减排列（参见 Validate 和 SortByStart）
+func (ft *FetchedTranscript) SnippetAt(t float64) (int, bool) {
+	snippets := ft.Snippets
+	// 第一个开始时间晚于 t 的片段
+	i := sort.Search(len(snippets), func(i int) bool {
+		return snippets[i].Start > t
+	})
+	if i == 0 {
+		return 0, false
+	}
+
+	// 回退到开始时间相同的一组片段的第一个
+	first := i - 1
+	for first > 0 && snippets[first-1].Start == snippets[i-1].Start {
+		first--
+	}
+	for j := first; j < i; j++ {
+		if t < snippets[j].Start+snippets[j].Duration {
+			return j, true
+		}
+	}
+	return 0, false
+}
+
+// TimedSnippet 带有绝对时间的字幕片段，参见 WithAbsoluteTimes
+type TimedSnippet struct {
+	FetchedTranscriptSnippet
+	// At 片段开始显示的绝对时间（直播开始时间加上 Start）
+	At time.Time
+}
+
+// WithAbsoluteTimes 以 streamStart 为起点为每个片段计算绝对时间，用于将直播回放的字幕与其他带时间戳的数据对应
+// 直播回放字幕的 Start 是相对于直播开始的，开始时间需要调用方提供，可以通过 StreamStartTime 从 FetchPlayerResponse 的结果中获取
+func (ft *FetchedTranscript) WithAbsoluteTimes(streamStart time.Time) []TimedSnippet {
+	snippets := make([]TimedSnippet, len(ft.Snippets))
+	for i, snippet := range ft.Snippets {
+		offset := time.Duration(math.Round(snippet.Start * float64(time.Second)))
+		snippets[i] = TimedSnippet{FetchedTranscriptSnippet: snippet, At: streamStart.Add(offset)}
+	}
+	return snippets
+}
+
+// StreamStartTime 从 FetchPlayerResponse 返回的 player 响应中提取直播的实际开始时间
+// （microformat.playerMicroformatRenderer.liveBroadcastDetails.startTimestamp），不是直播或没有该字段时返回 false
+func StreamStartTime(playerResponse map[string]interface{}) (time.Time, bool) {
+	timestamp, _ := jsonPath(playerResponse, "microformat", "playerMicroformatRenderer", "liveBroadcastDetails", "startTimestamp").(string)
+	if timestamp == "" {
+		return time.Time{}, false
+	}
+	start, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return start, true
+}
+
+// ContentHash 返回字幕片段内容的 SHA-256（小写十六进制），用于检测字幕是否变化或重复
+// 只包含片段，不包含标题、语言等元数据。规范化方式固定不变，以保证不同版本计算的结果一致：
+// 每个片段按顺序写入一行 "{start}\t{duration}\t{text}\n"，其中 start 和 duration 为四舍五入到毫秒的整数，
+// text 为 strconv.Quote 转义后的文本（带双引号），避免文本中的制表符或换行符造成歧义
+func (ft *FetchedTranscript) ContentHash() string {
+	hash := sha256.New()
+	for _, snippet := range ft.Snippets {
+		fmt.Fprintf(hash, "%d\t%d\t%s\n",
+			int64(math.Round(snippet.Start*1000)),
+			int64(math.Round(snippet.Duration*1000)),
+			strconv.Quote(snippet.Text))
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// maxSpeakerLabelLength 说话人标签的最大字符数
+const maxSpeakerLabelLength = 31
+
+// speakerLabelPatterns 识别说话人标签的规则：
+// ">> NAME:" 或 ">> Name Surname:"（">>" 表示换人说话，标签的每个词都需以大写字母开头，避免把 ">> and then he said:" 这样的句子当作标签），
+// 以及行首的全大写标签 "NAME:" / "- NAME:"
+var speakerLabelPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`>>\s*(\p{Lu}[\p{L}\p{N}.'’-]*(?: +\p{Lu}[\p{L}\p{N}.'’-]*)*)\s*:`),
+	regexp.MustCompile(`(?m)^\s*-?\s*([\p{Lu}][\p{Lu}\p{N}.'’ -]{0,30}?)\s*:`),
+}
+
+// Speakers 返回片段文本中出现过的说话人标签（按首次出现的顺序，忽略大小写去重，保留首次出现时的写法），没有时返回空切片
+// 采用启发式规则：识别 ">> NAME:" / ">> Name:"（全大写或每个词首字母大写）和行首的全大写 "NAME:"，
+// 标签最长 31 个字符、至少 2 个字符；只有字幕本身带有说话人标签时才有结果（通常是对话较多的手动字幕），
+// 自动生成的字幕一般没有；全大写的行首文本后跟冒号（如 "NOTE:"）也会被识别为说话人
+func (ft *FetchedTranscript) Speakers() []string {
+	speakers := []string{}
+	seen := map[string]bool{}
+	for _, snippet := range ft.Snippets {
+		for _, pattern := range speakerLabelPatterns {
+			for _, match := range pattern.FindAllStringSubmatch(snippet.Text, -1) {
+				speaker := strings.Join(strings.Fields(match[1]), " ")
+				key := strings.ToUpper(speaker)
+				if length := len([]rune(speaker)); length < 2 || length > maxSpeakerLabelLength || seen[key] {
+					continue
+				}
+				seen[key] = true
+				speakers = append(speakers, speaker)
+			}
+		}
+	}
+	return speakers
+}
@@ -1,8 +1,12 @@
 package youtube_transcript_api
 
 import (
+	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -12,10 +16,17 @@ type Formatter interface {
 	FormatTranscripts(transcripts []*FetchedTranscript) (string, error)
 }
 
+// ErrNilTranscript 传给 FormatTranscript 的字幕为 nil（通常是获取字幕出错后未检查错误）
+// FormatTranscripts 会跳过其中为 nil 的字幕
+var ErrNilTranscript = errors.New("cannot format a nil transcript")
+
 // JSONFormatter JSON 格式输出
 type JSONFormatter struct{}
 
 func (f *JSONFormatter) FormatTranscript(transcript *FetchedTranscript) (string, error) {
+	if transcript == nil {
+		return "", ErrNilTranscript
+	}
 	data := transcript.ToRawData()
 	jsonBytes, err := json.MarshalIndent(data, "", "  ")
 	if err != nil {
@@ -27,6 +38,9 @@ func (f *JSONFormatter) FormatTranscript(transcript *FetchedTranscript) (string,
 func (f *JSONFormatter) FormatTranscripts(transcripts []*FetchedTranscript) (string, error) {
 	var data []interface{}
 	for _, transcript := range transcripts {
+		if transcript == nil {
+			continue
+		}
 		data = append(data, transcript.ToRawData())
 	}
 	jsonBytes, err := json.MarshalIndent(data, "", "  ")
@@ -40,6 +54,9 @@ func (f *JSONFormatter) FormatTranscripts(transcripts []*FetchedTranscript) (str
 type PrettyPrintFormatter struct{}
 
 func (f *PrettyPrintFormatter) FormatTranscript(transcript *FetchedTranscript) (string, error) {
+	if transcript == nil {
+		return "", ErrNilTranscript
+	}
 	data := transcript.ToRawData()
 	jsonBytes, err := json.MarshalIndent(data, "", "  ")
 	if err != nil {
@@ -51,6 +68,9 @@ func (f *PrettyPrintFormatter) FormatTranscript(transcript *FetchedTranscript) (
 func (f *PrettyPrintFormatter) FormatTranscripts(transcripts []*FetchedTranscript) (string, error) {
 	var data []interface{}
 	for _, transcript := range transcripts {
+		if transcript == nil {
+			continue
+		}
 		data = append(data, transcript.ToRawData())
 	}
 	jsonBytes, err := json.MarshalIndent(data, "", "  ")
@@ -60,27 +80,122 @@ func (f *PrettyPrintFormatter) FormatTranscripts(transcripts []*FetchedTranscrip
 	return string(jsonBytes), nil
 }
 
+// indexedTranscript 索引 JSON 中单个视频的条目
+type indexedTranscript struct {
+	Title        string                   `json:"title"`
+	ThumbnailURL string                   `json:"thumbnail_url"`
+	VideoID      string                   `json:"video_id"`
+	Language     string                   `json:"language"`
+	LanguageCode string                   `json:"language_code"`
+	IsGenerated  bool                     `json:"is_generated"`
+	Snippets     []map[string]interface{} `json:"snippets"`
+}
+
+// FormatTranscriptsIndexed 将多个视频的字幕输出为以视频 ID 为键的 JSON 对象（包含元数据和字幕片段）
+// 键按字典序输出，保证结果可复现；值为 nil 的条目会被跳过
+func FormatTranscriptsIndexed(transcripts map[string]*FetchedTranscript) (string, error) {
+	index := make(map[string]indexedTranscript, len(transcripts))
+	for videoID, transcript := range transcripts {
+		if transcript == nil {
+			continue
+		}
+		index[videoID] = indexedTranscript{
+			Title:        transcript.Title,
+			ThumbnailURL: transcript.ThumbnailURL,
+			VideoID:      transcript.VideoID,
+			Language:     transcript.Language,
+			LanguageCode: transcript.LanguageCode,
+			IsGenerated:  transcript.IsGenerated,
+			Snippets:     transcript.ToRawData(),
+		}
+	}
+
+	// encoding/json 会按键排序输出 map
+	jsonBytes, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(jsonBytes), nil
+}
+
 // TextFormatter 纯文本格式（无时间戳）
-type TextFormatter struct{}
+type TextFormatter struct {
+	withHeader bool
+	// separators 自定义分隔符，为 nil 时使用默认分隔符
+	separators *textSeparators
+}
+
+// textSeparators TextFormatter 的片段分隔符和字幕之间的分隔符
+type textSeparators struct {
+	snippet    string
+	transcript string
+}
+
+// 默认的片段分隔符和 FormatTranscripts 中字幕之间的分隔符
+const (
+	DefaultTextSnippetSeparator    = "\n"
+	DefaultTextTranscriptSeparator = "\n\n\n"
+)
+
+// NewTextFormatterWithHeader 创建在正文前输出标题和视频链接的纯文本格式化器
+// 头部格式为 "# {Title}\n# https://youtu.be/{VideoID}\n\n"
+func NewTextFormatterWithHeader() *TextFormatter {
+	return &TextFormatter{withHeader: true}
+}
+
+// NewTextFormatterWithSeparator 创建使用 sep 连接字幕片段的纯文本格式化器（如 " " 输出为一个段落），字幕之间的分隔符保持默认
+func NewTextFormatterWithSeparator(sep string) *TextFormatter {
+	return NewTextFormatterWithSeparators(sep, DefaultTextTranscriptSeparator)
+}
+
+// NewTextFormatterWithSeparators 创建使用 sep 连接字幕片段、FormatTranscripts 中使用 transcriptSep 分隔各个字幕的纯文本格式化器
+func NewTextFormatterWithSeparators(sep, transcriptSep string) *TextFormatter {
+	return &TextFormatter{separators: &textSeparators{snippet: sep, transcript: transcriptSep}}
+}
+
+func (f *TextFormatter) snippetSeparator() string {
+	if f.separators == nil {
+		return DefaultTextSnippetSeparator
+	}
+	return f.separators.snippet
+}
+
+func (f *TextFormatter) transcriptSeparator() string {
+	if f.separators == nil {
+		return DefaultTextTranscriptSeparator
+	}
+	return f.separators.transcript
+}
 
 func (f *TextFormatter) FormatTranscript(transcript *FetchedTranscript) (string, error) {
+	if transcript == nil {
+		return "", ErrNilTranscript
+	}
 	var lines []string
 	for _, snippet := range transcript.Snippets {
 		lines = append(lines, snippet.Text)
 	}
-	return strings.Join(lines, "\n"), nil
+
+	text := strings.Join(lines, f.snippetSeparator())
+	if f.withHeader {
+		text = fmt.Sprintf("# %s\n# https://youtu.be/%s\n\n", transcript.Title, transcript.VideoID) + text
+	}
+	return text, nil
 }
 
 func (f *TextFormatter) FormatTranscripts(transcripts []*FetchedTranscript) (string, error) {
 	var sections []string
 	for _, transcript := range transcripts {
+		if transcript == nil {
+			continue
+		}
 		formatted, err := f.FormatTranscript(transcript)
 		if err != nil {
 			return "", err
 		}
 		sections = append(sections, formatted)
 	}
-	return strings.Join(sections, "\n\n\n"), nil
+	return strings.Join(sections, f.transcriptSeparator()), nil
 }
 
 // TextBasedFormatter 基于文本的格式化器基类（用于 SRT 和 WebVTT）
@@ -102,12 +217,7 @@ func (f *TextBasedFormatter) formatTranscript(transcript *FetchedTranscript, for
 	var lines []string
 	for i := range transcript.Snippets {
 		snippet := &transcript.Snippets[i]
-		end := snippet.Start + snippet.Duration
-
-		// 如果下一个片段的开始时间小于当前结束时间，使用下一个片段的开始时间
-		if i < len(transcript.Snippets)-1 && transcript.Snippets[i+1].Start < end {
-			end = transcript.Snippets[i+1].Start
-		}
+		end := snippetEnd(transcript.Snippets, i)
 
 		h1, m1, s1, ms1 := f.secondsToTimestamp(snippet.Start)
 		h2, m2, s2, ms2 := f.secondsToTimestamp(end)
@@ -123,6 +233,23 @@ func (f *TextBasedFormatter) formatTranscript(transcript *FetchedTranscript, for
 	return formatHeader(lines), nil
 }
 
+// snippetEnd 计算第 i 个片段的结束时间
+// 如果下一个片段的开始时间小于当前结束时间，使用下一个片段的开始时间，避免字幕重叠。
+// 开始时间相同的片段按原顺序输出，且不会互相截断（否则会得到时长为 0 的字幕），而是截断到之后第一个开始时间更晚的片段
+func snippetEnd(snippets []FetchedTranscriptSnippet, i int) float64 {
+	end := snippets[i].Start + snippets[i].Duration
+	for j := i + 1; j < len(snippets); j++ {
+		if snippets[j].Start == snippets[i].Start {
+			continue
+		}
+		if snippets[j].Start < end {
+			end = snippets[j].Start
+		}
+		break
+	}
+	return end
+}
+
 // SRTFormatter SRT 字幕文件格式
 type SRTFormatter struct {
 	*TextBasedFormatter
@@ -149,12 +276,18 @@ func (f *SRTFormatter) formatHelper(i int, timeText string, snippet *FetchedTran
 }
 
 func (f *SRTFormatter) FormatTranscript(transcript *FetchedTranscript) (string, error) {
+	if transcript == nil {
+		return "", ErrNilTranscript
+	}
 	return f.formatTranscript(transcript, f.formatTimestamp, f.formatHeader, f.formatHelper)
 }
 
 func (f *SRTFormatter) FormatTranscripts(transcripts []*FetchedTranscript) (string, error) {
 	var sections []string
 	for _, transcript := range transcripts {
+		if transcript == nil {
+			continue
+		}
 		formatted, err := f.FormatTranscript(transcript)
 		if err != nil {
 			return "", err
@@ -167,6 +300,8 @@ func (f *SRTFormatter) FormatTranscripts(transcripts []*FetchedTranscript) (stri
 // WebVTTFormatter WebVTT 字幕文件格式
 type WebVTTFormatter struct {
 	*TextBasedFormatter
+	style string
+	notes []string
 }
 
 func NewWebVTTFormatter() *WebVTTFormatter {
@@ -177,12 +312,44 @@ func NewWebVTTFormatter() *WebVTTFormatter {
 	}
 }
 
+// NewWebVTTFormatterWithStyle 创建在 WEBVTT 头之后、第一个字幕之前输出 STYLE 块和 NOTE 块的 WebVTT 格式化器
+// css 为 STYLE 块内容（如 "::cue { background: black; color: white; }"），为空时不输出；
+// 每条 note 输出为一个 NOTE 块。WebVTT 规定 STYLE 块必须位于所有字幕之前，
+// 因此会移除 css 中的空行、并将 "-->" 替换为 "->"，note 中的换行会被替换为空格
+func NewWebVTTFormatterWithStyle(css string, notes []string) *WebVTTFormatter {
+	f := NewWebVTTFormatter()
+	f.style = css
+	f.notes = notes
+	return f
+}
+
+// webVTTBlock 清理 STYLE / NOTE 块的内容：块内不能有空行，也不能包含 "-->"
+func webVTTBlock(text string, joinLines string) string {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		lines = append(lines, strings.ReplaceAll(line, "-->", "->"))
+	}
+	return strings.Join(lines, joinLines)
+}
+
 func (f *WebVTTFormatter) formatTimestamp(hours, mins, secs, ms int) string {
 	return fmt.Sprintf("%02d:%02d:%02d.%03d", hours, mins, secs, ms)
 }
 
 func (f *WebVTTFormatter) formatHeader(lines []string) string {
-	return "WEBVTT\n\n" + strings.Join(lines, "\n\n") + "\n"
+	header := "WEBVTT\n\n"
+	if css := webVTTBlock(f.style, "\n"); css != "" {
+		header += "STYLE\n" + css + "\n\n"
+	}
+	for _, note := range f.notes {
+		if note = webVTTBlock(note, " "); note != "" {
+			header += "NOTE " + note + "\n\n"
+		}
+	}
+	return header + strings.Join(lines, "\n\n") + "\n"
 }
 
 func (f *WebVTTFormatter) formatHelper(i int, timeText string, snippet *FetchedTranscriptSnippet) string {
@@ -190,12 +357,18 @@ func (f *WebVTTFormatter) formatHelper(i int, timeText string, snippet *FetchedT
 }
 
 func (f *WebVTTFormatter) FormatTranscript(transcript *FetchedTranscript) (string, error) {
+	if transcript == nil {
+		return "", ErrNilTranscript
+	}
 	return f.formatTranscript(transcript, f.formatTimestamp, f.formatHeader, f.formatHelper)
 }
 
 func (f *WebVTTFormatter) FormatTranscripts(transcripts []*FetchedTranscript) (string, error) {
 	var sections []string
 	for _, transcript := range transcripts {
+		if transcript == nil {
+			continue
+		}
 		formatted, err := f.FormatTranscript(transcript)
 		if err != nil {
 			return "", err
@@ -205,9 +378,169 @@ func (f *WebVTTFormatter) FormatTranscripts(transcripts []*FetchedTranscript) (s
 	return strings.Join(sections, "\n\n"), nil
 }
 
+// CSVFormatter CSV 格式，第一行为列名，每个字幕片段一行
+type CSVFormatter struct {
+	columns []string
+}
+
+// 默认的 CSV 列，以及包含序号和结束时间、便于时间轴分析的列
+var (
+	DefaultCSVColumns  = []string{"start", "duration", "text"}
+	TimelineCSVColumns = []string{"index", "start", "end", "duration", "text"}
+)
+
+// csvColumns CSV 支持的列
+var csvColumns = map[string]bool{"index": true, "start": true, "end": true, "duration": true, "text": true}
+
+// csvValue 返回第 i 个片段在指定列中的值
+func csvValue(column string, snippets []FetchedTranscriptSnippet, i int) string {
+	switch column {
+	case "index":
+		return strconv.Itoa(i)
+	case "start":
+		return formatCSVSeconds(snippets[i].Start)
+	case "end":
+		return formatCSVSeconds(snippetEnd(snippets, i))
+	case "duration":
+		return formatCSVSeconds(snippets[i].Duration)
+	default:
+		return snippets[i].Text
+	}
+}
+
+// NewCSVFormatter 创建使用 DefaultCSVColumns 的 CSV 格式化器
+func NewCSVFormatter() *CSVFormatter {
+	return &CSVFormatter{columns: DefaultCSVColumns}
+}
+
+// NewCSVFormatterWithColumns 创建按给定顺序输出指定列的 CSV 格式化器，可用的列为 index、start、end、duration、text
+// index 从 0 开始；end 与 SRT/WebVTT 使用的结束时间一致（与下一个片段重叠时截断到其开始时间）
+func NewCSVFormatterWithColumns(columns ...string) (*CSVFormatter, error) {
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("at least one CSV column is required")
+	}
+	for _, column := range columns {
+		if !csvColumns[column] {
+			return nil, fmt.Errorf("unknown CSV column '%s'. Choose from: index, start, end, duration, text", column)
+		}
+	}
+	return &CSVFormatter{columns: append([]string(nil), columns...)}, nil
+}
+
+// formatCSVSeconds 以最短的十进制形式输出秒数
+func formatCSVSeconds(seconds float64) string {
+	return strconv.FormatFloat(seconds, 'f', -1, 64)
+}
+
+func (f *CSVFormatter) columnNames() []string {
+	if len(f.columns) == 0 {
+		return DefaultCSVColumns
+	}
+	return f.columns
+}
+
+func (f *CSVFormatter) FormatTranscript(transcript *FetchedTranscript) (string, error) {
+	if transcript == nil {
+		return "", ErrNilTranscript
+	}
+	return f.format([]*FetchedTranscript{transcript}, false)
+}
+
+// FormatTranscripts 将多个字幕输出为一个 CSV，首列为 video_id 以区分各行所属的视频
+func (f *CSVFormatter) FormatTranscripts(transcripts []*FetchedTranscript) (string, error) {
+	return f.format(transcripts, true)
+}
+
+func (f *CSVFormatter) format(transcripts []*FetchedTranscript, withVideoID bool) (string, error) {
+	columns := f.columnNames()
+
+	var sb strings.Builder
+	writer := csv.NewWriter(&sb)
+
+	header := columns
+	if withVideoID {
+		header = append([]string{"video_id"}, columns...)
+	}
+	if err := writer.Write(header); err != nil {
+		return "", err
+	}
+
+	for _, transcript := range transcripts {
+		if transcript == nil {
+			continue
+		}
+		for i := range transcript.Snippets {
+			var record []string
+			if withVideoID {
+				record = append(record, transcript.VideoID)
+			}
+			for _, column := range columns {
+				record = append(record, csvValue(column, transcript.Snippets, i))
+			}
+			if err := writer.Write(record); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// formatterAliases 格式名称的常用别名
+var formatterAliases = map[string]string{
+	"vtt":    "webvtt",
+	"subrip": "srt",
+	"txt":    "text",
+}
+
+// textTransformFormatter 格式化前对每个片段的文本应用 transform 的包装格式化器
+type textTransformFormatter struct {
+	formatter Formatter
+	transform func(string) string
+}
+
+// WithTextTransform 包装 formatter，格式化时对每个字幕片段的文本应用 transform（如 strings.ToUpper、屏蔽敏感词、折行），
+// 只作用于输出，传入的字幕不会被修改。适用于任意格式化器，包括 FormatterLoader.Load 返回的格式化器
+func WithTextTransform(formatter Formatter, transform func(string) string) Formatter {
+	return &textTransformFormatter{formatter: formatter, transform: transform}
+}
+
+// apply 返回片段文本经过 transform 的字幕副本，nil 原样返回（交由被包装的格式化器处理）
+func (f *textTransformFormatter) apply(transcript *FetchedTranscript) *FetchedTranscript {
+	if transcript == nil {
+		return nil
+	}
+	snippets := make([]FetchedTranscriptSnippet, len(transcript.Snippets))
+	for i, snippet := range transcript.Snippets {
+		snippet.Text = f.transform(snippet.Text)
+		snippets[i] = snippet
+	}
+	return transcript.copyWithSnippets(snippets)
+}
+
+func (f *textTransformFormatter) FormatTranscript(transcript *FetchedTranscript) (string, error) {
+	return f.formatter.FormatTranscript(f.apply(transcript))
+}
+
+func (f *textTransformFormatter) FormatTranscripts(transcripts []*FetchedTranscript) (string, error) {
+	transformed := make([]*FetchedTranscript, len(transcripts))
+	for i, transcript := range transcripts {
+		transformed[i] = f.apply(transcript)
+	}
+	return f.formatter.FormatTranscripts(transformed)
+}
+
+// DefaultFormat FormatterLoader 默认使用的格式
+const DefaultFormat = "pretty"
+
 // FormatterLoader 格式化器加载器
 type FormatterLoader struct {
-	types map[string]func() Formatter
+	types       map[string]func() Formatter
+	defaultType string
 }
 
 // NewFormatterLoader 创建格式化器加载器
@@ -219,25 +552,147 @@ func NewFormatterLoader() *FormatterLoader {
 			"text":   func() Formatter { return &TextFormatter{} },
 			"webvtt": func() Formatter { return NewWebVTTFormatter() },
 			"srt":    func() Formatter { return NewSRTFormatter() },
+			"csv":    func() Formatter { return NewCSVFormatter() },
 		},
+		defaultType: DefaultFormat,
+	}
+}
+
+// NewFormatterLoaderWithDefault 创建格式化器加载器，并设置 Load("") 时使用的默认格式
+func NewFormatterLoaderWithDefault(formatterType string) (*FormatterLoader, error) {
+	fl := NewFormatterLoader()
+	if err := fl.SetDefault(formatterType); err != nil {
+		return nil, err
 	}
+	return fl, nil
+}
+
+// SetDefault 设置 Load("") 时使用的默认格式，格式名称必须已注册
+func (fl *FormatterLoader) SetDefault(formatterType string) error {
+	name, err := fl.resolve(formatterType)
+	if err != nil {
+		return err
+	}
+	fl.defaultType = name
+	return nil
+}
+
+// Default 返回当前的默认格式
+func (fl *FormatterLoader) Default() string {
+	return fl.defaultType
 }
 
 // Load 加载指定类型的格式化器
 func (fl *FormatterLoader) Load(formatterType string) (Formatter, error) {
 	if formatterType == "" {
-		formatterType = "pretty"
+		formatterType = fl.defaultType
+	}
+
+	name, err := fl.resolve(formatterType)
+	if err != nil {
+		return nil, err
 	}
 
-	formatterFactory, ok := fl.types[formatterType]
+	return fl.types[name](), nil
+}
+
+// FormatInfo 一种输出格式的说明，用于构建帮助信息或界面中的格式选择
+type FormatInfo struct {
+	// Name 格式名称，可传给 Load
+	Name string
+	// Extension 保存为文件时使用的扩展名（含 "."，如 ".srt"）
+	Extension string
+	// MIMEType 输出内容的 MIME 类型
+	MIMEType string
+	// Description 格式的简短英文说明
+	Description string
+}
+
+// formatInfos 内置格式的扩展名、MIME 类型和说明
+var formatInfos = map[string]FormatInfo{
+	"json":   {Extension: ".json", MIMEType: "application/json", Description: "JSON array of snippets with text, start and duration"},
+	"pretty": {Extension: ".json", MIMEType: "application/json", Description: "Indented JSON that is easy to read in a terminal"},
+	"text":   {Extension: ".txt", MIMEType: "text/plain", Description: "Plain text, one snippet per line without timestamps"},
+	"webvtt": {Extension: ".vtt", MIMEType: "text/vtt", Description: "WebVTT subtitles for HTML5 video players"},
+	"srt":    {Extension: ".srt", MIMEType: "application/x-subrip", Description: "SubRip subtitles supported by most video players"},
+	"csv":    {Extension: ".csv", MIMEType: "text/csv", Description: "CSV with start, duration and text columns"},
+}
+
+// formatInfo 返回已注册格式 name 的说明，没有说明的自定义格式使用 ".txt" 和 "text/plain"
+func formatInfo(name string) FormatInfo {
+	info, ok := formatInfos[name]
 	if !ok {
+		info = FormatInfo{Extension: ".txt", MIMEType: "text/plain"}
+	}
+	info.Name = name
+	return info
+}
+
+// FormatCatalog 返回所有已注册格式的说明，按名称排序
+func (fl *FormatterLoader) FormatCatalog() []FormatInfo {
+	names := make([]string, 0, len(fl.types))
+	for name := range fl.types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	catalog := make([]FormatInfo, 0, len(names))
+	for _, name := range names {
+		catalog = append(catalog, formatInfo(name))
+	}
+	return catalog
+}
+
+// Extension 返回指定格式保存为文件时使用的扩展名（不含 "."），未知扩展名的自定义格式使用 "txt"
+func (fl *FormatterLoader) Extension(formatterType string) (string, error) {
+	if formatterType == "" {
+		formatterType = fl.defaultType
+	}
+
+	name, err := fl.resolve(formatterType)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimPrefix(formatInfo(name).Extension, "."), nil
+}
+
+// resolve 将格式名称解析为已注册的名称（不区分大小写，并支持常用别名）
+func (fl *FormatterLoader) resolve(formatterType string) (string, error) {
+	name := strings.ToLower(strings.TrimSpace(formatterType))
+	if alias, ok := formatterAliases[name]; ok {
+		name = alias
+	}
+
+	if _, ok := fl.types[name]; !ok {
 		var supportedTypes []string
 		for k := range fl.types {
 			supportedTypes = append(supportedTypes, k)
 		}
-		return nil, fmt.Errorf("the format '%s' is not supported. Choose one of the following formats: %s",
+		sort.Strings(supportedTypes)
+		return "", fmt.Errorf("the format '%s' is not supported. Choose one of the following formats: %s",
 			formatterType, strings.Join(supportedTypes, ", "))
 	}
 
-	return formatterFactory(), nil
+	return name, nil
+}
+
+// ToJSON 使用 JSONFormatter 将字幕格式化为 JSON
+func (ft *FetchedTranscript) ToJSON() (string, error) {
+	return (&JSONFormatter{}).FormatTranscript(ft)
+}
+
+// ToText 使用 TextFormatter 将字幕格式化为纯文本
+func (ft *FetchedTranscript) ToText() (string, error) {
+	return (&TextFormatter{}).FormatTranscript(ft)
+}
+
+// ToSRT 使用 SRTFormatter 将字幕格式化为 SRT
+func (ft *FetchedTranscript) ToSRT() (string, error) {
+	return NewSRTFormatter().FormatTranscript(ft)
+}
+
+// ToWebVTT 使用 WebVTTFormatter 将字幕格式化为 WebVTT
+func (ft *FetchedTranscript) ToWebVTT() (string, error) {
+	return NewWebVTTFormatter().FormatTranscript(ft)
 }
@@ -1,10 +1,17 @@
 package youtube_transcript_api
 
 import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
 	"io"
+	"net"
 	"net/http"
 	"net/http/cookiejar"
 	"net/url"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -15,6 +22,42 @@ type HTTPClient struct {
 	HTTPProxy  *url.URL
 	HTTPSProxy *url.URL
 	Jar        *cookiejar.Jar
+	// HTTP1Only 强制使用 HTTP/1.1，用于处理对 HTTP/2 支持不佳的代理
+	HTTP1Only bool
+	// MaxRetries 请求失败时的最大重试次数，0 表示不重试
+	MaxRetries int
+	// RetryBackoff 首次重试前的等待时间，之后每次翻倍
+	RetryBackoff time.Duration
+	// MaxRetryBackoff 单次重试等待时间的上限，0 表示不限制
+	MaxRetryBackoff time.Duration
+	// RetryBudget 一个重试循环（从第一次尝试开始）允许花费的总时间，剩余时间不足以等待下一次重试时直接返回最后一次的结果，0 表示不限制
+	RetryBudget time.Duration
+	// RetryIf 判断一次请求结果是否需要重试，为 nil 时使用 DefaultRetryIf
+	RetryIf func(resp *http.Response, err error) bool
+	// ReadTimeout 读取响应体时两次收到数据之间允许的最长间隔，超时后中止读取，0 表示不限制
+	ReadTimeout time.Duration
+	// SharedTransport 不为 nil 时所有请求使用该 Transport（及其连接池），可在多个客户端之间共享。
+	// 此时代理、HTTP1Only 和连接阶段的设置不会生效，需要在共享的 Transport 上自行配置（NewYouTubeTranscriptApi 拒绝这种组合）；请求头、Cookie 和重试设置仍属于各个客户端
+	SharedTransport *http.Transport
+	// DialTimeout、TLSHandshakeTimeout、ResponseHeaderTimeout 分别限制建立连接、TLS 握手和等待响应头的时间，0 表示不单独限制
+	DialTimeout           time.Duration
+	TLSHandshakeTimeout   time.Duration
+	ResponseHeaderTimeout time.Duration
+	// LocalAddr 建立连接时使用的本地地址，用于在多出口 IP 的主机上指定源 IP，为 nil 时由系统选择
+	LocalAddr net.Addr
+
+	// VideoProxy 不为 nil 时，带有视频 ID 的请求（获取字幕列表和字幕内容）使用它为该视频返回的代理，
+	// 返回 nil 时使用 HTTPProxy / HTTPSProxy。由 SetupHTTPClientProxy 根据 PerVideoProxyConfig 设置
+	VideoProxy func(videoID string) *url.URL
+
+	transport *http.Transport
+	// transportMu 保护 transport：批量任务的 worker 通过 parent 并发使用同一个 Transport，Close 也可能在其他 goroutine 中调用
+	transportMu sync.Mutex
+	// parent 不为 nil 时（批量任务的 worker 客户端）使用 parent 的 Transport，parent Close 后同样返回 ErrClientClosed，
+	// 连接池由 parent 持有，关闭 parent 即可释放 worker 建立的所有连接
+	parent *HTTPClient
+	// closed Close 之后为 true，所有请求返回 ErrClientClosed；Close 可以与正在进行的请求并发调用
+	closed atomic.Bool
 }
 
 // NewHTTPClient 创建新的 HTTP 客户端
@@ -30,59 +73,333 @@ func NewHTTPClient() (*HTTPClient, error) {
 	}
 
 	return &HTTPClient{
-		client:  client,
-		Headers: make(map[string]string),
-		Jar:     jar,
+		client:       client,
+		Headers:      make(map[string]string),
+		Jar:          jar,
+		RetryBackoff: time.Second,
 	}, nil
 }
 
+// Reset 清空 Cookie（重建 Jar）并丢弃已创建的 Transport 及其空闲连接，请求头、代理、重试和超时等设置保持不变
+// SharedTransport 由调用方管理，不会被关闭或替换。客户端仍是同一个对象，引用它的 Transcript 在 Reset 后可以继续使用；
+// 已经 Close 的客户端返回 ErrClientClosed
+func (c *HTTPClient) Reset() error {
+	if c.isClosed() {
+		return ErrClientClosed
+	}
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return err
+	}
+	c.Jar = jar
+	c.client.Jar = jar
+	c.resetTransport()
+	return nil
+}
+
+// ErrClientClosed 客户端（或所属的 YouTubeTranscriptApi 实例）已经 Close，不能再发送请求
+var ErrClientClosed = errors.New("the HTTP client has been closed")
+
+// Close 关闭客户端：释放空闲连接，之后的所有请求都返回 ErrClientClosed。Close 是幂等的，关闭后不能重新打开
+// 可以在其他 goroutine 使用该客户端（包括批量任务的 worker）时调用；SharedTransport 由调用方管理，不会被关闭
+func (c *HTTPClient) Close() {
+	c.closed.Store(true)
+	c.resetTransport()
+}
+
+// isClosed 判断客户端（或其 parent）是否已经 Close
+func (c *HTTPClient) isClosed() bool {
+	return c.closed.Load() || (c.parent != nil && c.parent.isClosed())
+}
+
 // Get 发送 GET 请求
 func (c *HTTPClient) Get(url string) (*http.Response, error) {
-	req, err := http.NewRequest("GET", url, nil)
+	return c.GetContext(context.Background(), url)
+}
+
+// GetContext 发送带 context 的 GET 请求
+func (c *HTTPClient) GetContext(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	c.setHeaders(req, nil)
+
+	return c.do(req)
+}
+
+// Post 发送 POST 请求
+func (c *HTTPClient) Post(url string, contentType string, body io.Reader) (*http.Response, error) {
+	return c.PostContext(context.Background(), url, contentType, body)
+}
+
+// PostContext 发送带 context 的 POST 请求
+func (c *HTTPClient) PostContext(ctx context.Context, url string, contentType string, body io.Reader) (*http.Response, error) {
+	return c.postWithHeaders(ctx, url, contentType, body, nil)
+}
+
+// postWithHeaders 发送 POST 请求，headers 会覆盖同名的默认请求头
+func (c *HTTPClient) postWithHeaders(ctx context.Context, url string, contentType string, body io.Reader, headers map[string]string) (*http.Response, error) {
+	// 缓存请求体，以便重试时重新发送
+	var bodyBytes []byte
+	if body != nil {
+		var err error
+		bodyBytes, err = io.ReadAll(body)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(bodyBytes))
 	if err != nil {
 		return nil, err
 	}
 
-	// 设置请求头
+	c.setHeaders(req, headers)
+	req.Header.Set("Content-Type", contentType)
+
+	return c.do(req)
+}
+
+// setHeaders 设置请求头，优先级从低到高：Headers、WithRequestHeaders 附加到 ctx 的请求头、headers
+func (c *HTTPClient) setHeaders(req *http.Request, headers map[string]string) {
 	for k, v := range c.Headers {
 		req.Header.Set(k, v)
 	}
+	for k, v := range requestHeaders(req.Context()) {
+		req.Header.Set(k, v)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+}
 
-	// 设置代理
-	transport := &http.Transport{}
-	if c.HTTPProxy != nil {
-		transport.Proxy = http.ProxyURL(c.HTTPProxy)
+func (c *HTTPClient) do(req *http.Request) (*http.Response, error) {
+	if c.isClosed() {
+		return nil, ErrClientClosed
 	}
-	if c.HTTPSProxy != nil {
-		transport.Proxy = http.ProxyURL(c.HTTPSProxy)
+	c.client.Transport = c.getTransport()
+
+	start := time.Now()
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+
+		resp, err := c.client.Do(req)
+		if attempt >= c.MaxRetries || !c.shouldRetry(resp, err) || !c.retryBudgetAllows(start, attempt) {
+			return resp, err
+		}
+
+		// 丢弃本次响应，等待后重试
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		if err := c.sleepBackoff(req.Context(), attempt); err != nil {
+			return nil, err
+		}
 	}
-	c.client.Transport = transport
+}
 
-	return c.client.Do(req)
+// retryBackoff 返回第 attempt 次重试前的退避时间（RetryBackoff * 2^attempt，不超过 MaxRetryBackoff）
+func (c *HTTPClient) retryBackoff(attempt int) time.Duration {
+	backoff := c.RetryBackoff * time.Duration(1<<attempt)
+	if c.MaxRetryBackoff > 0 && (backoff > c.MaxRetryBackoff || backoff < 0) {
+		backoff = c.MaxRetryBackoff
+	}
+	return backoff
 }
 
-// Post 发送 POST 请求
-func (c *HTTPClient) Post(url string, contentType string, body io.Reader) (*http.Response, error) {
-	req, err := http.NewRequest("POST", url, body)
+// retryBudgetAllows 判断从 start 开始的重试循环在等待第 attempt 次重试的退避时间后是否仍在 RetryBudget 之内
+func (c *HTTPClient) retryBudgetAllows(start time.Time, attempt int) bool {
+	return c.RetryBudget <= 0 || time.Since(start)+c.retryBackoff(attempt) <= c.RetryBudget
+}
+
+// sleepBackoff 等待第 attempt 次重试前的退避时间（参见 retryBackoff），ctx 取消时立即返回错误
+func (c *HTTPClient) sleepBackoff(ctx context.Context, attempt int) error {
+	select {
+	case <-time.After(c.retryBackoff(attempt)):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *HTTPClient) shouldRetry(resp *http.Response, err error) bool {
+	if c.RetryIf != nil {
+		return c.RetryIf(resp, err)
+	}
+	return DefaultRetryIf(resp, err)
+}
+
+// DefaultRetryIf 默认的重试判断：网络错误、被限流（429）以及 5xx 服务端错误
+func DefaultRetryIf(resp *http.Response, err error) bool {
 	if err != nil {
-		return nil, err
+		return true
 	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
 
-	// 设置请求头
-	for k, v := range c.Headers {
-		req.Header.Set(k, v)
+// getTransport 返回复用的 Transport，首次调用时根据当前配置创建
+func (c *HTTPClient) getTransport() *http.Transport {
+	if c.parent != nil {
+		return c.parent.getTransport()
+	}
+	if c.SharedTransport != nil {
+		return c.SharedTransport
+	}
+	c.transportMu.Lock()
+	defer c.transportMu.Unlock()
+	if c.transport == nil {
+		if c.closed.Load() {
+			// Close 之前已经开始的请求使用不保留连接的临时 Transport，避免 Close 之后留下空闲连接
+			transport := c.buildTransport()
+			transport.DisableKeepAlives = true
+			return transport
+		}
+		c.transport = c.buildTransport()
+	}
+	return c.transport
+}
+
+// resetTransport 丢弃已创建的 Transport，下次请求时按最新配置重建（SharedTransport 不受影响）
+func (c *HTTPClient) resetTransport() {
+	c.transportMu.Lock()
+	defer c.transportMu.Unlock()
+	if c.transport != nil {
+		c.transport.CloseIdleConnections()
+	}
+	c.transport = nil
+}
+
+func (c *HTTPClient) buildTransport() *http.Transport {
+	transport := &http.Transport{
+		TLSHandshakeTimeout:   c.TLSHandshakeTimeout,
+		ResponseHeaderTimeout: c.ResponseHeaderTimeout,
+	}
+	if dialer := c.dialer(); dialer != nil {
+		transport.DialContext = dialer.DialContext
+		// 自定义 DialContext 会关闭 Transport 自动启用的 HTTP/2，需要显式开启（WithHTTP1Only 时除外）
+		transport.ForceAttemptHTTP2 = !c.HTTP1Only
 	}
-	req.Header.Set("Content-Type", contentType)
 
 	// 设置代理
-	transport := &http.Transport{}
+	if c.HTTPProxy != nil || c.HTTPSProxy != nil || c.VideoProxy != nil {
+		transport.Proxy = c.proxyForRequest
+	}
+
+	// 强制 HTTP/1.1：关闭 HTTP/2 协商
+	if c.HTTP1Only {
+		transport.ForceAttemptHTTP2 = false
+		transport.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
+	}
+
+	return transport
+}
+
+// dialer 根据 DialTimeout 和 LocalAddr 创建自定义 Dialer，两者都未设置时返回 nil（使用默认 Dialer）
+func (c *HTTPClient) dialer() *net.Dialer {
+	if c.DialTimeout <= 0 && c.LocalAddr == nil {
+		return nil
+	}
+	return &net.Dialer{Timeout: c.DialTimeout, LocalAddr: c.LocalAddr}
+}
+
+func (c *HTTPClient) proxyForRequest(req *http.Request) (*url.URL, error) {
+	if c.VideoProxy != nil {
+		if videoID := requestVideoID(req.Context()); videoID != "" {
+			if proxyURL := c.VideoProxy(videoID); proxyURL != nil {
+				return proxyURL, nil
+			}
+		}
+	}
+	if req.URL.Scheme == "https" && c.HTTPSProxy != nil {
+		return c.HTTPSProxy, nil
+	}
 	if c.HTTPProxy != nil {
-		transport.Proxy = http.ProxyURL(c.HTTPProxy)
+		return c.HTTPProxy, nil
+	}
+	return c.HTTPSProxy, nil
+}
+
+// videoIDContextKey 请求 context 中保存视频 ID 的键
+type videoIDContextKey struct{}
+
+// withRequestVideoID 在 ctx 中记录请求所属的视频 ID，供 VideoProxy 选择代理
+func withRequestVideoID(ctx context.Context, videoID string) context.Context {
+	return context.WithValue(ctx, videoIDContextKey{}, videoID)
+}
+
+// requestVideoID 返回 withRequestVideoID 记录的视频 ID，没有时返回空字符串
+func requestVideoID(ctx context.Context) string {
+	videoID, _ := ctx.Value(videoIDContextKey{}).(string)
+	return videoID
+}
+
+// requestHeadersContextKey 请求 context 中保存附加请求头的键
+type requestHeadersContextKey struct{}
+
+// WithRequestHeaders 返回附加了 headers 的 ctx：使用该 ctx 发出的所有请求（包括 List / Fetch 等方法内部的请求及其重试）
+// 都会带上这些请求头，可用于按调用设置追踪 ID 等信息，而无需修改共享的客户端配置。
+// 这些请求头覆盖 HTTPClient.Headers 中的同名请求头，但不会覆盖本库为特定请求设置的请求头（如 InnerTube 客户端标识、Content-Type）。
+// 多次调用时合并，后附加的同名请求头覆盖先附加的
+func WithRequestHeaders(ctx context.Context, headers map[string]string) context.Context {
+	merged := make(map[string]string, len(headers))
+	for k, v := range requestHeaders(ctx) {
+		merged[k] = v
+	}
+	for k, v := range headers {
+		merged[k] = v
 	}
-	if c.HTTPSProxy != nil {
-		transport.Proxy = http.ProxyURL(c.HTTPSProxy)
+	return context.WithValue(ctx, requestHeadersContextKey{}, merged)
+}
+
+// requestHeaders 返回 WithRequestHeaders 附加到 ctx 的请求头
+func requestHeaders(ctx context.Context) map[string]string {
+	headers, _ := ctx.Value(requestHeadersContextKey{}).(map[string]string)
+	return headers
+}
+
+// ErrReadTimeout 读取响应体时超过 ReadTimeout 没有收到数据
+var ErrReadTimeout = errors.New("timed out waiting for response body data")
+
+// readBody 读取并关闭响应体；设置了 ReadTimeout 时，数据停止到达超过该时间后中止读取并返回 ErrReadTimeout
+// 用于防止代理以极慢的速度逐字节返回数据，使请求在客户端总超时内长时间挂起
+func (c *HTTPClient) readBody(body io.ReadCloser) ([]byte, error) {
+	defer body.Close()
+	if c.ReadTimeout <= 0 {
+		return io.ReadAll(body)
 	}
-	c.client.Transport = transport
 
-	return c.client.Do(req)
+	var expired int32
+	timer := time.AfterFunc(c.ReadTimeout, func() {
+		atomic.StoreInt32(&expired, 1)
+		body.Close()
+	})
+	defer timer.Stop()
+
+	var buf bytes.Buffer
+	chunk := make([]byte, 32*1024)
+	for {
+		n, err := body.Read(chunk)
+		if n > 0 {
+			timer.Reset(c.ReadTimeout)
+			buf.Write(chunk[:n])
+		}
+		if atomic.LoadInt32(&expired) == 1 {
+			return nil, ErrReadTimeout
+		}
+		if err == io.EOF {
+			return buf.Bytes(), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
 }
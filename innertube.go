@@ -0,0 +1,107 @@
+package youtube_transcript_api
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// InnertubeClient 描述请求 InnerTube API 时使用的客户端
+type InnertubeClient struct {
+	Name    string            // clientName，例如 "ANDROID"
+	Version string            // clientVersion，例如 "20.10.38"
+	Headers map[string]string // 额外的请求头，会覆盖根据客户端生成的默认请求头
+}
+
+// 常用的 InnerTube 客户端
+var (
+	InnertubeClientAndroid  = InnertubeClient{Name: "ANDROID", Version: "20.10.38"}
+	InnertubeClientWeb      = InnertubeClient{Name: "WEB", Version: "2.20250312.04.00"}
+	InnertubeClientIOS      = InnertubeClient{Name: "IOS", Version: "20.10.4"}
+	InnertubeClientEmbedded = InnertubeClient{Name: "WEB_EMBEDDED_PLAYER", Version: "1.20250310.01.00"}
+)
+
+// innertubeClientIDs clientName 对应的 X-Youtube-Client-Name 数字 ID
+var innertubeClientIDs = map[string]int{
+	"WEB":                 1,
+	"ANDROID":             3,
+	"IOS":                 5,
+	"WEB_EMBEDDED_PLAYER": 56,
+}
+
+// defaultInnertubeClient 返回 InnertubeContext 中配置的默认客户端
+func defaultInnertubeClient() InnertubeClient {
+	client := InnertubeClientAndroid
+	if ctx, ok := InnertubeContext["context"].(map[string]interface{}); ok {
+		if clientMap, ok := ctx["client"].(map[string]interface{}); ok {
+			if name, ok := clientMap["clientName"].(string); ok {
+				client.Name = name
+			}
+			if version, ok := clientMap["clientVersion"].(string); ok {
+				client.Version = version
+			}
+		}
+	}
+	return client
+}
+
+// requestContext 构建 InnerTube 请求体中的 context 字段
+func (c InnertubeClient) requestContext(videoID string) map[string]interface{} {
+	requestContext := map[string]interface{}{
+		"client": map[string]interface{}{
+			"clientName":    c.Name,
+			"clientVersion": c.Version,
+		},
+	}
+	// 嵌入式播放器需要提供嵌入页面的地址
+	if c.Name == InnertubeClientEmbedded.Name {
+		requestContext["thirdParty"] = map[string]interface{}{
+			"embedUrl": fmt.Sprintf(EmbedURLTemplate, videoID),
+		}
+	}
+	return requestContext
+}
+
+// requestHeaders 构建与客户端匹配的请求头（Origin、Referer、X-Youtube-Client-*），再合并自定义请求头
+func (c InnertubeClient) requestHeaders(videoID string) map[string]string {
+	headers := map[string]string{
+		"Origin":                   "https://www.youtube.com",
+		"Referer":                  fmt.Sprintf(WatchURLTemplate, videoID),
+		"X-Youtube-Client-Version": c.Version,
+	}
+	if c.Name == InnertubeClientEmbedded.Name {
+		headers["Referer"] = fmt.Sprintf(EmbedURLTemplate, videoID)
+	}
+	if id, ok := innertubeClientIDs[c.Name]; ok {
+		headers["X-Youtube-Client-Name"] = strconv.Itoa(id)
+	}
+	for k, v := range c.Headers {
+		headers[k] = v
+	}
+	return headers
+}
+
+var (
+	watchPageClientNamePattern    = regexp.MustCompile(`"INNERTUBE_CLIENT_NAME":\s*"([A-Z_]+)"`)
+	watchPageClientVersionPattern = regexp.MustCompile(`"INNERTUBE_CLIENT_VERSION":\s*"([0-9.]+)"`)
+)
+
+// extractInnertubeClientVersion 从视频页面的配置中提取页面使用的客户端名称和版本，提取失败时 ok 为 false
+func extractInnertubeClientVersion(html string) (name, version string, ok bool) {
+	nameMatches := watchPageClientNamePattern.FindStringSubmatch(html)
+	versionMatches := watchPageClientVersionPattern.FindStringSubmatch(html)
+	if len(nameMatches) != 2 || len(versionMatches) != 2 {
+		return "", "", false
+	}
+	return nameMatches[1], versionMatches[1], true
+}
+
+// withDetectedVersion 如果视频页面使用的客户端与 c 相同，返回使用页面中版本号的客户端，否则原样返回
+// 视频页面上的配置是 WEB 客户端的，因此只对 WEB 客户端生效，其他客户端（如默认的 ANDROID）继续使用固定版本
+func (c InnertubeClient) withDetectedVersion(html string) InnertubeClient {
+	name, version, ok := extractInnertubeClientVersion(html)
+	if ok && name == c.Name {
+		c.Version = version
+	}
+	return c
+}
@@ -0,0 +1,139 @@
+package youtube_transcript_api
+
+import (
+	"strings"
+	"unicode"
+)
+
+// scriptLanguages 非拉丁文字对应的语言代码（按文字直接判断）
+var scriptLanguages = []struct {
+	table *unicode.RangeTable
+	code  string
+}{
+	{unicode.Hiragana, "ja"},
+	{unicode.Katakana, "ja"},
+	{unicode.Hangul, "ko"},
+	{unicode.Han, "zh"},
+	{unicode.Cyrillic, "ru"},
+	{unicode.Arabic, "ar"},
+	{unicode.Devanagari, "hi"},
+	{unicode.Thai, "th"},
+	{unicode.Greek, "el"},
+	{unicode.Hebrew, "iw"},
+}
+
+// stopwords 拉丁文字语言的高频词表，用于比较各语言的词命中率
+var stopwords = map[string][]string{
+	"en": {"the", "and", "is", "to", "of", "you", "that", "it", "in", "this", "what", "we", "for", "are", "was", "with", "have", "be", "not", "on"},
+	"es": {"el", "la", "de", "que", "y", "en", "los", "es", "un", "por", "con", "para", "una", "las", "del", "se", "lo", "pero", "muy", "como"},
+	"fr": {"le", "la", "les", "de", "et", "est", "un", "une", "des", "que", "je", "pas", "vous", "pour", "dans", "ce", "il", "du", "sur", "nous"},
+	"de": {"der", "die", "und", "das", "ist", "ich", "nicht", "es", "sie", "zu", "den", "mit", "ein", "wir", "auf", "auch", "von", "eine", "dass", "sich"},
+	"it": {"il", "di", "che", "la", "e", "non", "un", "per", "sono", "una", "mi", "ho", "io", "questo", "del", "lo", "gli", "con", "le", "ma"},
+	"pt": {"o", "de", "que", "e", "a", "do", "da", "em", "um", "para", "não", "uma", "os", "com", "no", "se", "na", "por", "mais", "você"},
+	"nl": {"de", "het", "een", "en", "van", "ik", "is", "dat", "niet", "je", "op", "te", "zijn", "met", "voor", "wat", "maar", "er", "ook", "we"},
+	"id": {"yang", "dan", "di", "ini", "itu", "dengan", "untuk", "tidak", "ada", "saya", "kita", "akan", "dari", "ke", "kamu", "juga", "apa", "bisa", "sudah", "kami"},
+	"tr": {"bir", "ve", "bu", "da", "de", "ne", "için", "çok", "ben", "mi", "var", "ama", "gibi", "daha", "sen", "o", "değil", "olarak", "ile", "her"},
+	"pl": {"nie", "się", "to", "jest", "w", "na", "i", "że", "z", "co", "jak", "ale", "tak", "do", "mnie", "o", "ja", "już", "tym", "czy"},
+}
+
+// stopwordSets stopwords 的集合形式
+var stopwordSets = func() map[string]map[string]bool {
+	sets := make(map[string]map[string]bool, len(stopwords))
+	for code, words := range stopwords {
+		set := make(map[string]bool, len(words))
+		for _, word := range words {
+			set[word] = true
+		}
+		sets[code] = set
+	}
+	return sets
+}()
+
+// DetectLanguage 根据字幕文本粗略推测内容的语言，返回语言代码和 0 到 1 之间的置信度
+// 非拉丁文字（中文、日文、韩文、西里尔、阿拉伯等）按文字判断，返回 scriptLanguages 中的语言；
+// 拉丁文字只在 stopwords 列出的 10 种语言（en、es、fr、de、it、pt、nl、id、tr、pl）之间比较常用词的命中率，
+// 不是通用的拉丁文字语言检测：其他拉丁文字语言（如瑞典语、越南语）会返回这 10 种中的某一种或 ("", 0)。
+// 这是一个轻量级的启发式检测：文本过短或混合语言时结果不可靠，
+// 无法区分使用同一文字的语言（如俄语与乌克兰语会都返回 "ru"），无法判断时返回 ("", 0)
+func (ft *FetchedTranscript) DetectLanguage() (string, float64) {
+	var text strings.Builder
+	for _, snippet := range ft.Snippets {
+		text.WriteString(snippet.Text)
+		text.WriteByte(' ')
+	}
+
+	if code, confidence := detectScript(text.String()); code != "" {
+		return code, confidence
+	}
+	return detectLatinLanguage(text.String())
+}
+
+// detectScript 统计各文字的字符数，非拉丁文字占多数时返回对应语言
+func detectScript(text string) (string, float64) {
+	counts := make(map[string]int)
+	letters := 0
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		for _, script := range scriptLanguages {
+			if unicode.Is(script.table, r) {
+				counts[script.code]++
+				break
+			}
+		}
+	}
+	if letters == 0 {
+		return "", 0
+	}
+
+	// 日文中夹杂大量汉字，只要有假名就归为日文
+	if counts["ja"] > 0 {
+		counts["ja"] += counts["zh"]
+		delete(counts, "zh")
+	}
+
+	bestCode, bestCount := "", 0
+	for code, count := range counts {
+		if count > bestCount || (count == bestCount && code < bestCode) {
+			bestCode, bestCount = code, count
+		}
+	}
+	if bestCount*2 <= letters {
+		return "", 0
+	}
+	return bestCode, float64(bestCount) / float64(letters)
+}
+
+// detectLatinLanguage 比较 stopwords 中各语言常用词在文本中的命中次数，只能返回 stopwords 中的语言
+func detectLatinLanguage(text string) (string, float64) {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	})
+	if len(words) == 0 {
+		return "", 0
+	}
+
+	hits := make(map[string]int)
+	total := 0
+	for _, word := range words {
+		for code, set := range stopwordSets {
+			if set[word] {
+				hits[code]++
+				total++
+			}
+		}
+	}
+	if total == 0 {
+		return "", 0
+	}
+
+	bestCode, bestHits := "", 0
+	for code, count := range hits {
+		if count > bestHits || (count == bestHits && code < bestCode) {
+			bestCode, bestHits = code, count
+		}
+	}
+	return bestCode, float64(bestHits) / float64(total)
+}
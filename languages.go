@@ -0,0 +1,232 @@
+package youtube_transcript_api
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// languageNames 常用 BCP-47 语言代码到英文名称的映射（覆盖 YouTube 字幕和翻译语言中常见的代码）
+var languageNames = map[string]string{
+	"af":      "Afrikaans",
+	"ak":      "Akan",
+	"am":      "Amharic",
+	"ar":      "Arabic",
+	"as":      "Assamese",
+	"ay":      "Aymara",
+	"az":      "Azerbaijani",
+	"be":      "Belarusian",
+	"bg":      "Bulgarian",
+	"bho":     "Bhojpuri",
+	"bn":      "Bangla",
+	"bs":      "Bosnian",
+	"ca":      "Catalan",
+	"ceb":     "Cebuano",
+	"co":      "Corsican",
+	"cs":      "Czech",
+	"cy":      "Welsh",
+	"da":      "Danish",
+	"de":      "German",
+	"dv":      "Divehi",
+	"ee":      "Ewe",
+	"el":      "Greek",
+	"en":      "English",
+	"en-GB":   "English (United Kingdom)",
+	"en-US":   "English (United States)",
+	"eo":      "Esperanto",
+	"es":      "Spanish",
+	"es-419":  "Spanish (Latin America)",
+	"es-ES":   "Spanish (Spain)",
+	"et":      "Estonian",
+	"eu":      "Basque",
+	"fa":      "Persian",
+	"fi":      "Finnish",
+	"fil":     "Filipino",
+	"fr":      "French",
+	"fr-CA":   "French (Canada)",
+	"fy":      "Western Frisian",
+	"ga":      "Irish",
+	"gd":      "Scottish Gaelic",
+	"gl":      "Galician",
+	"gn":      "Guarani",
+	"gu":      "Gujarati",
+	"ha":      "Hausa",
+	"haw":     "Hawaiian",
+	"hi":      "Hindi",
+	"hmn":     "Hmong",
+	"hr":      "Croatian",
+	"ht":      "Haitian Creole",
+	"hu":      "Hungarian",
+	"hy":      "Armenian",
+	"id":      "Indonesian",
+	"ig":      "Igbo",
+	"is":      "Icelandic",
+	"it":      "Italian",
+	"iw":      "Hebrew",
+	"he":      "Hebrew",
+	"ja":      "Japanese",
+	"jv":      "Javanese",
+	"ka":      "Georgian",
+	"kk":      "Kazakh",
+	"km":      "Khmer",
+	"kn":      "Kannada",
+	"ko":      "Korean",
+	"kri":     "Krio",
+	"ku":      "Kurdish",
+	"ky":      "Kyrgyz",
+	"la":      "Latin",
+	"lb":      "Luxembourgish",
+	"lg":      "Luganda",
+	"ln":      "Lingala",
+	"lo":      "Lao",
+	"lt":      "Lithuanian",
+	"lv":      "Latvian",
+	"mg":      "Malagasy",
+	"mi":      "Māori",
+	"mk":      "Macedonian",
+	"ml":      "Malayalam",
+	"mn":      "Mongolian",
+	"mr":      "Marathi",
+	"ms":      "Malay",
+	"mt":      "Maltese",
+	"my":      "Burmese",
+	"ne":      "Nepali",
+	"nl":      "Dutch",
+	"no":      "Norwegian",
+	"nso":     "Northern Sotho",
+	"ny":      "Nyanja",
+	"om":      "Oromo",
+	"or":      "Odia",
+	"pa":      "Punjabi",
+	"pl":      "Polish",
+	"ps":      "Pashto",
+	"pt":      "Portuguese",
+	"pt-BR":   "Portuguese (Brazil)",
+	"pt-PT":   "Portuguese (Portugal)",
+	"qu":      "Quechua",
+	"ro":      "Romanian",
+	"ru":      "Russian",
+	"rw":      "Kinyarwanda",
+	"sa":      "Sanskrit",
+	"sd":      "Sindhi",
+	"si":      "Sinhala",
+	"sk":      "Slovak",
+	"sl":      "Slovenian",
+	"sm":      "Samoan",
+	"sn":      "Shona",
+	"so":      "Somali",
+	"sq":      "Albanian",
+	"sr":      "Serbian",
+	"st":      "Southern Sotho",
+	"su":      "Sundanese",
+	"sv":      "Swedish",
+	"sw":      "Swahili",
+	"ta":      "Tamil",
+	"te":      "Telugu",
+	"tg":      "Tajik",
+	"th":      "Thai",
+	"ti":      "Tigrinya",
+	"tk":      "Turkmen",
+	"tr":      "Turkish",
+	"ts":      "Tsonga",
+	"tt":      "Tatar",
+	"ug":      "Uyghur",
+	"uk":      "Ukrainian",
+	"ur":      "Urdu",
+	"uz":      "Uzbek",
+	"vi":      "Vietnamese",
+	"xh":      "Xhosa",
+	"yi":      "Yiddish",
+	"yo":      "Yoruba",
+	"zh":      "Chinese",
+	"zh-CN":   "Chinese (China)",
+	"zh-HK":   "Chinese (Hong Kong)",
+	"zh-Hans": "Chinese (Simplified)",
+	"zh-Hant": "Chinese (Traditional)",
+	"zh-TW":   "Chinese (Taiwan)",
+	"zu":      "Zulu",
+}
+
+// languageNamesLower 以小写语言代码为键的查找表，用于不区分大小写的查找
+var languageNamesLower = func() map[string]string {
+	result := make(map[string]string, len(languageNames))
+	for code, name := range languageNames {
+		result[strings.ToLower(code)] = name
+	}
+	return result
+}()
+
+// LanguageName 返回语言代码对应的英文名称，如 "pt-BR" 返回 "Portuguese (Brazil)"
+// 查找不区分大小写，也接受 "_" 作为分隔符；表中没有完整代码时回退到主语言子标签（如 "de-AT" 返回 "German"）
+func LanguageName(code string) (string, bool) {
+	code = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "_", "-"))
+	if name, ok := languageNamesLower[code]; ok {
+		return name, true
+	}
+	if i := strings.Index(code, "-"); i > 0 {
+		if name, ok := languageNamesLower[code[:i]]; ok {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// acceptLanguageTagPattern 匹配 Accept-Language 中合法的语言标签（如 "en"、"en-US"、"zh-Hant-TW"）
+var acceptLanguageTagPattern = regexp.MustCompile(`^[A-Za-z]{1,8}(-[A-Za-z0-9]{1,8})*$`)
+
+// ParseAcceptLanguage 将 HTTP Accept-Language 请求头解析为按 q 值从高到低排序的语言代码列表，可直接作为 Fetch 的 languages 参数
+// q 值相同时保持请求头中的顺序；q=0 的语言、通配符 "*" 和格式错误的片段会被忽略，重复的语言只保留第一次出现。
+// 带地区的代码（如 "en-US"）之后未单独列出的基础语言（"en"）会追加到列表末尾，因为 YouTube 字幕通常只使用基础语言代码
+func ParseAcceptLanguage(header string) []string {
+	type weightedLanguage struct {
+		code string
+		q    float64
+	}
+
+	var languages []weightedLanguage
+	seen := make(map[string]bool)
+	for _, segment := range strings.Split(header, ",") {
+		parts := strings.Split(segment, ";")
+		code := strings.ReplaceAll(strings.TrimSpace(parts[0]), "_", "-")
+		if !acceptLanguageTagPattern.MatchString(code) {
+			continue
+		}
+
+		q, ok := 1.0, true
+		for _, param := range parts[1:] {
+			name, value, found := strings.Cut(strings.TrimSpace(param), "=")
+			if !found || !strings.EqualFold(strings.TrimSpace(name), "q") {
+				continue
+			}
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil || parsed < 0 || parsed > 1 {
+				ok = false
+				break
+			}
+			q = parsed
+		}
+		if !ok || q == 0 || seen[strings.ToLower(code)] {
+			continue
+		}
+		seen[strings.ToLower(code)] = true
+		languages = append(languages, weightedLanguage{code: code, q: q})
+	}
+
+	sort.SliceStable(languages, func(i, j int) bool {
+		return languages[i].q > languages[j].q
+	})
+
+	codes := make([]string, 0, len(languages))
+	for _, language := range languages {
+		codes = append(codes, language.code)
+	}
+	for _, language := range languages {
+		base, _, hasRegion := strings.Cut(language.code, "-")
+		if hasRegion && !seen[strings.ToLower(base)] {
+			seen[strings.ToLower(base)] = true
+			codes = append(codes, base)
+		}
+	}
+	return codes
+}
@@ -0,0 +1,344 @@
+package youtube_transcript_api
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Option YouTubeTranscriptApi 的可选配置项
+type Option func(*apiOptions)
+
+// apiOptions 保存通过 Option 设置的配置
+type apiOptions struct {
+	http1Only          bool
+	maxRetries         int
+	retryBackoff       time.Duration
+	retryIf            func(resp *http.Response, err error) bool
+	embedFallback      bool
+	defaultLanguages   []string
+	languageFallback   []string
+	innertubeClient    *InnertubeClient
+	emptyBodyRetries   *int
+	captionCache       CaptionCache
+	readTimeout        time.Duration
+	detectVersion      bool
+	playabilityRetryIf func(unplayable *VideoUnplayable) bool
+	refreshOnForbidden bool
+	sharedTransport    *http.Transport
+	clientFallback     []InnertubeClient
+	dialTimeout        time.Duration
+	tlsTimeout         time.Duration
+	headerTimeout      time.Duration
+	localAddr          net.Addr
+	maxRetryBackoff    time.Duration
+	retryBudget        time.Duration
+	minSnippetDuration float64
+	contentParser      TranscriptContentParser
+	cookies            []importedCookie
+	batchDuplicates    bool
+	batchMaxSnippets   int64
+	batchMaxBytes      int64
+}
+
+func newAPIOptions(opts []Option) *apiOptions {
+	options := &apiOptions{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(options)
+		}
+	}
+	return options
+}
+
+// WithHTTP1Only 强制使用 HTTP/1.1 发送请求
+// 部分（尤其是住宅轮换）代理对 HTTP/2 处理有问题，会导致请求卡住，遇到这种情况时使用该选项
+func WithHTTP1Only() Option {
+	return func(o *apiOptions) {
+		o.http1Only = true
+	}
+}
+
+// WithRetries 设置单个 HTTP 请求失败时的最大重试次数，backoff 为首次重试前的等待时间（之后每次翻倍）
+// 默认不重试；哪些失败会触发重试由 WithRetryIf 决定（默认为 DefaultRetryIf）
+func WithRetries(maxRetries int, backoff time.Duration) Option {
+	return func(o *apiOptions) {
+		o.maxRetries = maxRetries
+		o.retryBackoff = backoff
+	}
+}
+
+// WithMaxRetryBackoff 设置单次重试等待时间的上限，避免重试次数较多时指数退避的等待时间过长
+func WithMaxRetryBackoff(maxBackoff time.Duration) Option {
+	return func(o *apiOptions) {
+		o.maxRetryBackoff = maxBackoff
+	}
+}
+
+// WithRetryBudget 设置重试的总时间预算：单个请求的重试、空响应体重试以及获取字幕列表时的重试（被阻止、可重试的播放状态），
+// 从第一次尝试开始计算，剩余时间不足以等待下一次重试时直接返回最后一次的错误，即使还有剩余的重试次数。
+// 可以避免批量任务中个别视频长时间占用 worker
+func WithRetryBudget(budget time.Duration) Option {
+	return func(o *apiOptions) {
+		o.retryBudget = budget
+	}
+}
+
+// WithRetryIf 自定义重试判断，覆盖默认的 DefaultRetryIf（网络错误、429、5xx）
+// 需要配合 WithRetries 设置重试次数才会生效
+func WithRetryIf(retryIf func(resp *http.Response, err error) bool) Option {
+	return func(o *apiOptions) {
+		o.retryIf = retryIf
+	}
+}
+
+// WithBatchDuplicates 批量任务（FetchBatch、FetchStreamBatch、ListBatch）不对视频 ID 去重，重复的 ID 各自请求一次
+// 默认重复的 ID 只请求一次，以减少请求数和被阻止的风险
+func WithBatchDuplicates() Option {
+	return func(o *apiOptions) {
+		o.batchDuplicates = true
+	}
+}
+
+// WithBatchLimits 限制一次批量获取（FetchBatch、FetchStreamBatch）累计获取的片段数和字节数（片段文本的 UTF-8 字节数），<= 0 表示不限制（默认）
+// 累计值超过任一上限后不再开始新的视频，剩余视频的 Err 为 LimitExceeded；超过上限的那个视频以及正在进行的视频仍返回各自的结果，
+// 因此实际获取的总量可能略高于上限。用于防止不可信的输入中少数超长视频耗尽内存和时间
+func WithBatchLimits(maxSnippets int, maxBytes int64) Option {
+	return func(o *apiOptions) {
+		o.batchMaxSnippets = int64(maxSnippets)
+		o.batchMaxBytes = maxBytes
+	}
+}
+
+// WithEmbedFallback 遇到年龄限制视频时，尝试以嵌入式播放器客户端重新请求字幕
+// 嵌入式播放器有时可以绕过年龄验证获取字幕；回退失败时仍返回 AgeRestricted 错误
+func WithEmbedFallback() Option {
+	return func(o *apiOptions) {
+		o.embedFallback = true
+	}
+}
+
+// WithDefaultLanguages 设置 Fetch 未传入语言时使用的默认语言列表（按优先级排序）
+// 未设置时使用包级默认值 DefaultLanguages（["en"]）
+func WithDefaultLanguages(languages []string) Option {
+	return func(o *apiOptions) {
+		o.defaultLanguages = append([]string(nil), languages...)
+	}
+}
+
+// languages 返回默认语言列表
+func (o *apiOptions) languages() []string {
+	if len(o.defaultLanguages) > 0 {
+		return o.defaultLanguages
+	}
+	return DefaultLanguages
+}
+
+// WithInnertubeClient 设置请求 InnerTube API 时使用的客户端（默认为 InnertubeContext 中的 ANDROID 客户端）
+// 请求会自动携带与该客户端匹配的 Origin、Referer 和 X-Youtube-Client-* 请求头，
+// client.Headers 中的值会覆盖这些默认请求头
+func WithInnertubeClient(client InnertubeClient) Option {
+	return func(o *apiOptions) {
+		o.innertubeClient = &client
+	}
+}
+
+// captionOptions 返回获取字幕内容时使用的设置，未设置的项使用默认值
+func (o *apiOptions) captionOptions() captionOptions {
+	opts := defaultCaptionOptions
+	opts.parser = o.contentParser
+	opts.minSnippetDuration = o.minSnippetDuration
+	opts.cache = o.captionCache
+	if o.emptyBodyRetries != nil {
+		opts.emptyBodyRetries = *o.emptyBodyRetries
+	}
+	return opts
+}
+
+// client 返回请求 InnerTube API 时使用的客户端
+func (o *apiOptions) client() InnertubeClient {
+	if o.innertubeClient != nil {
+		return *o.innertubeClient
+	}
+	return defaultInnertubeClient()
+}
+
+// WithClientFallback 设置获取字幕列表时依次尝试的 InnerTube 客户端（如 ANDROID、WEB、IOS），使用第一个成功的结果
+// 某个客户端被阻止、需要 PO Token 或返回其他错误时尝试下一个，全部失败时返回最后一个客户端的错误；
+// 视频页面和 API Key 只获取一次，所有客户端共用。设置后优先于 WithInnertubeClient，
+// FetchPlayerResponse 和 Diagnose 只使用链中的第一个客户端
+func WithClientFallback(clients []InnertubeClient) Option {
+	return func(o *apiOptions) {
+		o.clientFallback = append([]InnertubeClient(nil), clients...)
+	}
+}
+
+// clients 返回获取字幕列表时依次尝试的客户端，未设置 WithClientFallback 时只有 client()
+func (o *apiOptions) clients() []InnertubeClient {
+	if len(o.clientFallback) > 0 {
+		return o.clientFallback
+	}
+	return []InnertubeClient{o.client()}
+}
+
+// WithLanguageFallback 设置请求的语言都找不到字幕时依次尝试的备用语言链
+// 链中可以使用翻译指令，如 []string{"ja", "en", "auto-translate:en"}，
+// 其中 "auto-translate:en" 表示将任意可翻译的字幕翻译为英语
+func WithLanguageFallback(chain []string) Option {
+	return func(o *apiOptions) {
+		o.languageFallback = append([]string(nil), chain...)
+	}
+}
+
+// WithEmptyBodyRetries 设置字幕接口返回 200 但响应体为空时的重试次数（默认 DefaultEmptyBodyRetries），0 表示不重试
+func WithEmptyBodyRetries(retries int) Option {
+	return func(o *apiOptions) {
+		o.emptyBodyRetries = &retries
+	}
+}
+
+// WithMinSnippetDuration 解析字幕时丢弃持续时间小于 duration（秒）的片段，如 0.1 可以过滤掉大部分无意义的残留片段
+// 过滤在解析之后立即进行（包括 WithTranscriptContentParser 设置的自定义解析器），之后的处理和格式化器都不会看到这些片段；默认不过滤
+func WithMinSnippetDuration(duration float64) Option {
+	return func(o *apiOptions) {
+		o.minSnippetDuration = duration
+	}
+}
+
+// WithTranscriptContentParser 使用自定义解析器解析字幕响应体（默认使用 DefaultTranscriptContentParser）
+// 验证码页面检测仍然生效，WithMinSnippetDuration 作用于解析器返回的片段。
+// 需要调整内置解析器的设置（如 WithDecodeEntities）时传入 TranscriptParser.ContentParser 的返回值
+func WithTranscriptContentParser(parser TranscriptContentParser) Option {
+	return func(o *apiOptions) {
+		o.contentParser = parser
+	}
+}
+
+// WithCaptionCache 设置字幕响应体缓存，获取字幕时优先从缓存读取，成功获取后写入缓存
+func WithCaptionCache(cache CaptionCache) Option {
+	return func(o *apiOptions) {
+		o.captionCache = cache
+	}
+}
+
+// WithPlayabilityRetryIf 设置判断 VideoUnplayable 错误是否可重试的函数
+// 可重试时会重新获取视频页面和 Innertube 数据，重试次数和等待时间与 WithRetries 相同（未设置 WithRetries 时不重试）
+func WithPlayabilityRetryIf(retryIf func(unplayable *VideoUnplayable) bool) Option {
+	return func(o *apiOptions) {
+		o.playabilityRetryIf = retryIf
+	}
+}
+
+// WithRetryablePlayabilityReasons 将 reason 或任一 subreason 与给定文本一致（不区分大小写）的 VideoUnplayable 视为可重试，
+// 例如 "This video is currently unavailable"。参见 WithPlayabilityRetryIf
+func WithRetryablePlayabilityReasons(reasons ...string) Option {
+	return WithPlayabilityRetryIf(func(unplayable *VideoUnplayable) bool {
+		for _, reason := range reasons {
+			reason = strings.TrimSpace(reason)
+			if strings.EqualFold(unplayable.Reason, reason) {
+				return true
+			}
+			for _, subReason := range unplayable.SubReasons {
+				if strings.EqualFold(strings.TrimSpace(subReason), reason) {
+					return true
+				}
+			}
+		}
+		return false
+	})
+}
+
+// WithReadTimeout 设置读取响应体时两次收到数据之间允许的最长间隔，数据停止到达超过该时间后中止请求
+func WithReadTimeout(timeout time.Duration) Option {
+	return func(o *apiOptions) {
+		o.readTimeout = timeout
+	}
+}
+
+// WithDialTimeout 设置建立 TCP 连接（使用代理时为连接代理）的超时时间，默认不单独限制
+// 与 WithTLSHandshakeTimeout、WithResponseHeaderTimeout 一样只作用于连接阶段，整个请求仍受客户端 30 秒总超时限制；
+// 这些设置应用于实例自己的 Transport，不能与 WithSharedTransport 同时使用（参见 ErrSharedTransportConflict）
+func WithDialTimeout(timeout time.Duration) Option {
+	return func(o *apiOptions) {
+		o.dialTimeout = timeout
+	}
+}
+
+// WithTLSHandshakeTimeout 设置 TLS 握手的超时时间，默认不单独限制
+func WithTLSHandshakeTimeout(timeout time.Duration) Option {
+	return func(o *apiOptions) {
+		o.tlsTimeout = timeout
+	}
+}
+
+// WithResponseHeaderTimeout 设置发送请求后等待响应头的超时时间，默认不单独限制
+// 批量任务中可以设置较短的值，快速发现失效的代理，而不影响读取较大的响应体
+func WithResponseHeaderTimeout(timeout time.Duration) Option {
+	return func(o *apiOptions) {
+		o.headerTimeout = timeout
+	}
+}
+
+// WithLocalAddr 设置建立连接时使用的本地地址（如 &net.TCPAddr{IP: net.ParseIP("192.0.2.10")}），
+// 在多出口 IP 的主机上指定 YouTube 请求的源 IP，可为多个实例设置不同地址以分散请求；使用代理时为连接代理的源地址。
+// 与 WithDialTimeout 一样不能与 WithSharedTransport 同时使用
+func WithLocalAddr(addr net.Addr) Option {
+	return func(o *apiOptions) {
+		o.localAddr = addr
+	}
+}
+
+// WithClientVersionDetection 从视频页面中提取当前的 InnerTube 客户端版本并用于 Innertube 请求，提取失败时使用固定版本
+// 视频页面中的版本是 WEB 客户端的，因此需要配合 WithInnertubeClient(InnertubeClientWeb) 使用，对其他客户端没有影响
+func WithClientVersionDetection() Option {
+	return func(o *apiOptions) {
+		o.detectVersion = true
+	}
+}
+
+// WithRefreshOnForbidden 字幕 URL 返回 403 时重新获取一次字幕列表，使用新的字幕 URL 重试一次
+// 字幕 URL 中带有签名和过期时间，List 与 Fetch 间隔较长（如大批量任务被限流时）可能已经失效。
+// 重新获取列表会额外发送请求，因此默认关闭；重新获取失败或找不到同一字幕轨道时返回原始的 403 错误
+func WithRefreshOnForbidden() Option {
+	return func(o *apiOptions) {
+		o.refreshOnForbidden = true
+	}
+}
+
+// WithSharedTransport 使用给定的 Transport 发送请求，多个实例可以共享同一个连接池，减少大量实例时的连接开销
+// 共享的只有 Transport（连接池、代理、TLS 和 HTTP/2 配置）；请求头、Cookie、重试和总超时设置仍由各实例独立持有。
+// 代理、HTTP/1.1 和连接阶段的设置需要在创建 Transport 时自行配置：与代理配置（包括 HashedProxyPool）、WithHTTP1Only、
+// WithDialTimeout、WithTLSHandshakeTimeout、WithResponseHeaderTimeout 或 WithLocalAddr 同时使用时，
+// NewYouTubeTranscriptApi 返回 ErrSharedTransportConflict，而不是悄悄忽略这些设置
+func WithSharedTransport(transport *http.Transport) Option {
+	return func(o *apiOptions) {
+		o.sharedTransport = transport
+	}
+}
+
+// ErrSharedTransportConflict WithSharedTransport 与只能作用于实例自己的 Transport 的设置（代理、WithHTTP1Only、连接超时、本地地址）同时使用
+var ErrSharedTransportConflict = errors.New("the setting cannot be applied to a shared transport")
+
+// sharedTransportConflict 设置了 WithSharedTransport 时返回与之冲突的设置名称，没有冲突时返回空字符串
+func (o *apiOptions) sharedTransportConflict(proxyConfig ProxyConfig) string {
+	if o.sharedTransport == nil {
+		return ""
+	}
+	switch {
+	case proxyConfig != nil:
+		return "proxy config"
+	case o.http1Only:
+		return "WithHTTP1Only"
+	case o.dialTimeout > 0:
+		return "WithDialTimeout"
+	case o.tlsTimeout > 0:
+		return "WithTLSHandshakeTimeout"
+	case o.headerTimeout > 0:
+		return "WithResponseHeaderTimeout"
+	case o.localAddr != nil:
+		return "WithLocalAddr"
+	}
+	return ""
+}
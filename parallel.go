@@ -0,0 +1,67 @@
+package youtube_transcript_api
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// ParallelSnippet MergeParallel 得到的一对时间对齐的字幕文本，可直接序列化为 JSON 用于构建平行语料
+type ParallelSnippet struct {
+	// Start、End 这一对文本覆盖的时间范围（秒），为两侧所有组成片段时间范围的并集
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+	// TextA、TextB 分别来自 a 和 b 的文本，某一侧没有对应片段时为空字符串
+	TextA string `json:"text_a"`
+	TextB string `json:"text_b"`
+}
+
+// MergeParallel 将两种语言的字幕（如原文与翻译）按时间对齐为文本对
+// 不同语言的片段边界通常不一致，因此按重叠时间对齐：b 的每个片段归入与它重叠时间最长的 a 片段
+// （重叠相同时取较早的一个），归入同一个 a 片段的多个 b 片段按顺序以空格拼接为 TextB。
+// 与任何 b 片段都不重叠的 a 片段得到空的 TextB，与任何 a 片段都不重叠的 b 片段单独成对、TextA 为空。
+// 片段的结束时间与格式化器一致（显示时间与下一个片段重叠时截止到下一个片段开始），
+// 因此两份字幕都应按开始时间排序（参见 SortByStart）。结果按 Start 排序；a 或 b 为 nil 时返回 ErrNilTranscript
+func MergeParallel(a, b *FetchedTranscript) ([]ParallelSnippet, error) {
+	if a == nil || b == nil {
+		return nil, ErrNilTranscript
+	}
+
+	// assigned[i] 为归入 a 的第 i 个片段的 b 片段下标
+	assigned := make([][]int, len(a.Snippets))
+	var unmatched []ParallelSnippet
+	for j, snippetB := range b.Snippets {
+		startB, endB := snippetB.Start, snippetEnd(b.Snippets, j)
+		best, bestOverlap := -1, 0.0
+		for i, snippetA := range a.Snippets {
+			overlap := math.Min(endB, snippetEnd(a.Snippets, i)) - math.Max(startB, snippetA.Start)
+			if overlap > bestOverlap {
+				best, bestOverlap = i, overlap
+			}
+		}
+		if best < 0 {
+			unmatched = append(unmatched, ParallelSnippet{Start: startB, End: endB, TextB: snippetB.Text})
+			continue
+		}
+		assigned[best] = append(assigned[best], j)
+	}
+
+	pairs := make([]ParallelSnippet, 0, len(a.Snippets)+len(unmatched))
+	for i, snippetA := range a.Snippets {
+		pair := ParallelSnippet{Start: snippetA.Start, End: snippetEnd(a.Snippets, i), TextA: snippetA.Text}
+		texts := make([]string, 0, len(assigned[i]))
+		for _, j := range assigned[i] {
+			pair.Start = math.Min(pair.Start, b.Snippets[j].Start)
+			pair.End = math.Max(pair.End, snippetEnd(b.Snippets, j))
+			texts = append(texts, b.Snippets[j].Text)
+		}
+		pair.TextB = strings.Join(texts, " ")
+		pairs = append(pairs, pair)
+	}
+	pairs = append(pairs, unmatched...)
+
+	sort.SliceStable(pairs, func(i, j int) bool {
+		return pairs[i].Start < pairs[j].Start
+	})
+	return pairs, nil
+}
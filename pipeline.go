@@ -0,0 +1,124 @@
+package youtube_transcript_api
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// TranscriptTransform 字幕后处理函数，接收字幕并返回处理后的新字幕
+type TranscriptTransform func(*FetchedTranscript) *FetchedTranscript
+
+// TranscriptPipeline 按顺序执行的一组字幕后处理函数
+// 处理顺序会影响结果，例如先 strip-music 再 merge 可以避免把 [Music] 合并进正文，
+// 而 dedupe 应在 merge 之前执行，否则滚动字幕的重复内容会被合并进同一个片段而无法识别
+type TranscriptPipeline struct {
+	Transforms []TranscriptTransform
+}
+
+// NewTranscriptPipeline 使用给定的处理函数创建处理管道
+func NewTranscriptPipeline(transforms ...TranscriptTransform) *TranscriptPipeline {
+	return &TranscriptPipeline{Transforms: transforms}
+}
+
+// NewTranscriptPipelineFromNames 根据内置处理函数的名称（如 "strip-music"、"dedupe"、"merge"）创建处理管道
+// 名称不区分大小写，未知名称会返回错误
+func NewTranscriptPipelineFromNames(names []string) (*TranscriptPipeline, error) {
+	pipeline := NewTranscriptPipeline()
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		transform, ok := builtinTransforms[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown postprocess transform '%s', supported: %s", name, strings.Join(TranscriptTransformNames(), ", "))
+		}
+		pipeline.Add(transform)
+	}
+	return pipeline, nil
+}
+
+// Add 在管道末尾追加处理函数
+func (p *TranscriptPipeline) Add(transform TranscriptTransform) *TranscriptPipeline {
+	p.Transforms = append(p.Transforms, transform)
+	return p
+}
+
+// Apply 依次执行所有处理函数，返回最终结果；transcript 为 nil 时返回 nil
+func (p *TranscriptPipeline) Apply(transcript *FetchedTranscript) *FetchedTranscript {
+	for _, transform := range p.Transforms {
+		if transcript == nil {
+			return nil
+		}
+		transcript = transform(transcript)
+	}
+	return transcript
+}
+
+// DefaultMergeDuration 内置 merge 处理合并后单个片段的最长时长（秒）
+const DefaultMergeDuration = 10.0
+
+// builtinTransforms 可通过名称使用的内置处理函数
+var builtinTransforms = map[string]TranscriptTransform{
+	"strip-music": (*FetchedTranscript).StripNonSpeech,
+	"dedupe":      (*FetchedTranscript).Deduplicate,
+	"normalize":   (*FetchedTranscript).NormalizeWhitespace,
+	"merge": func(ft *FetchedTranscript) *FetchedTranscript {
+		return ft.Merge(DefaultMergeDuration)
+	},
+}
+
+// TranscriptTransformNames 返回所有内置处理函数的名称（已排序）
+func TranscriptTransformNames() []string {
+	names := make([]string, 0, len(builtinTransforms))
+	for name := range builtinTransforms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// nonSpeechPattern 匹配只包含非语音标注的片段，如 "[Music]"、"(Applause)"、"♪♪"
+var nonSpeechPattern = regexp.MustCompile(`^(?:\s*(?:\[[^\]]*\]|\([^)]*\)|[♪♫]+)\s*)+$`)
+
+// StripNonSpeech 移除只包含非语音标注（如 [Music]、(Applause)、♪）的字幕片段
+func (ft *FetchedTranscript) StripNonSpeech() *FetchedTranscript {
+	return ft.Filter(func(snippet FetchedTranscriptSnippet) bool {
+		return !nonSpeechPattern.MatchString(snippet.Text)
+	})
+}
+
+// NormalizeWhitespace 将片段文本中的连续空白（包括换行）合并为单个空格，并移除处理后为空的片段，其余片段保持原顺序
+func (ft *FetchedTranscript) NormalizeWhitespace() *FetchedTranscript {
+	var snippets []FetchedTranscriptSnippet
+	for _, snippet := range ft.Snippets {
+		snippet.Text = strings.Join(strings.Fields(snippet.Text), " ")
+		if snippet.Text != "" {
+			snippets = append(snippets, snippet)
+		}
+	}
+	return ft.copyWithSnippets(snippets)
+}
+
+// Merge 将相邻片段合并为更长的片段，合并后每个片段从开始到结束不超过 maxDuration 秒
+// 单个片段本身超过 maxDuration 时保持不变
+func (ft *FetchedTranscript) Merge(maxDuration float64) *FetchedTranscript {
+	var snippets []FetchedTranscriptSnippet
+	for _, snippet := range ft.Snippets {
+		if len(snippets) > 0 {
+			last := &snippets[len(snippets)-1]
+			end := snippet.Start + snippet.Duration
+			if end-last.Start <= maxDuration {
+				last.Text = last.Text + " " + snippet.Text
+				if end > last.Start+last.Duration {
+					last.Duration = end - last.Start
+				}
+				continue
+			}
+		}
+		snippets = append(snippets, snippet)
+	}
+	return ft.copyWithSnippets(snippets)
+}
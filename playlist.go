@@ -0,0 +1,258 @@
+package youtube_transcript_api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// maxPlaylistPages 获取播放列表时最多请求的页数（包括播放列表页面），防止续页令牌异常时无限循环
+// 每页约 100 个视频；达到上限时仍有下一页的播放列表返回 PlaylistTruncated
+const maxPlaylistPages = 200
+
+// ListPlaylistVideoIDs 获取播放列表中所有视频的 ID（按播放列表中的顺序，去除重复），可以直接传给 FetchBatch
+// playlistID 可以是播放列表 ID（如 "PL..."）或包含 list= 参数的播放列表 / 视频 URL。
+// 通过抓取播放列表页面和 InnerTube browse 接口（续页令牌）实现，属于尽力而为：YouTube 页面结构变化时可能失效。
+// 私享、已删除等无法播放的条目会被跳过；播放列表不存在或是私享的时返回 PlaylistUnavailable。
+// 续页令牌重复出现时停止分页；请求了 maxPlaylistPages 页后仍有下一页时返回已获取的视频 ID 和 PlaylistTruncated
+func (api *YouTubeTranscriptApi) ListPlaylistVideoIDs(playlistID string) ([]string, error) {
+	return api.ListPlaylistVideoIDsContext(context.Background(), playlistID)
+}
+
+// ListPlaylistVideoIDsContext 与 ListPlaylistVideoIDs 相同，ctx 取消时中止请求
+func (api *YouTubeTranscriptApi) ListPlaylistVideoIDsContext(ctx context.Context, playlistID string) ([]string, error) {
+	return api.fetcher.listPlaylistVideoIDs(ctx, playlistIDFromURL(playlistID))
+}
+
+// playlistIDFromURL 从包含 list= 参数的 URL 中提取播放列表 ID，不是 URL 时原样返回
+func playlistIDFromURL(playlistID string) string {
+	playlistID = strings.TrimSpace(playlistID)
+	if !strings.Contains(playlistID, "list=") {
+		return playlistID
+	}
+	u, err := url.Parse(playlistID)
+	if err != nil {
+		return playlistID
+	}
+	if list := u.Query().Get("list"); list != "" {
+		return list
+	}
+	return playlistID
+}
+
+func (tlf *TranscriptListFetcher) listPlaylistVideoIDs(ctx context.Context, playlistID string) ([]string, error) {
+	tlf.stats = FetchStats{}
+	pageURL := fmt.Sprintf(PlaylistURLTemplate, url.QueryEscape(playlistID))
+	html, err := tlf.fetchPageHTML(ctx, pageURL, playlistID)
+	if err != nil {
+		return nil, asPlaylistError(err)
+	}
+
+	initialData, err := extractYtInitialData(html, playlistID)
+	if err != nil {
+		return nil, err
+	}
+	page := parsePlaylistPage(initialData)
+	if !page.hasVideoList {
+		return nil, NewPlaylistUnavailable(playlistID, page.alert)
+	}
+
+	var videoIDs []string
+	seen := map[string]bool{}
+	addVideoIDs := func(ids []string) {
+		for _, id := range ids {
+			if !seen[id] {
+				seen[id] = true
+				videoIDs = append(videoIDs, id)
+			}
+		}
+	}
+	addVideoIDs(page.videoIDs)
+
+	if page.continuation == "" {
+		return videoIDs, nil
+	}
+	apiKey, err := tlf.extractInnertubeAPIKey(html, playlistID)
+	if err != nil {
+		return nil, err
+	}
+	client := InnertubeClientWeb.withDetectedVersion(html)
+	usedTokens := map[string]bool{}
+	for pages := 1; page.continuation != "" && !usedTokens[page.continuation] && pages < maxPlaylistPages; pages++ {
+		usedTokens[page.continuation] = true
+		data, err := tlf.fetchPlaylistContinuation(ctx, playlistID, pageURL, apiKey, client, page.continuation)
+		if err != nil {
+			return nil, err
+		}
+		page = parsePlaylistPage(data)
+		addVideoIDs(page.videoIDs)
+	}
+	if page.continuation != "" && !usedTokens[page.continuation] {
+		return videoIDs, NewPlaylistTruncated(playlistID, len(videoIDs))
+	}
+	return videoIDs, nil
+}
+
+// fetchPlaylistContinuation 使用续页令牌请求 InnerTube browse 接口，返回下一页的数据
+func (tlf *TranscriptListFetcher) fetchPlaylistContinuation(ctx context.Context, playlistID, pageURL, apiKey string, client InnertubeClient, token string) (map[string]interface{}, error) {
+	requestBody := map[string]interface{}{
+		"context":      client.requestContext(playlistID),
+		"continuation": token,
+	}
+	jsonData, err := json.Marshal(requestBody)
+	if err != nil {
+		return nil, NewPlaylistRequestFailed(playlistID, err)
+	}
+
+	headers := client.requestHeaders(playlistID)
+	headers["Referer"] = pageURL
+	resp, err := tlf.httpClient.postWithHeaders(ctx, fmt.Sprintf(InnertubeBrowseURLTemplate, apiKey), "application/json", strings.NewReader(string(jsonData)), headers)
+	if err != nil {
+		return nil, NewPlaylistRequestFailed(playlistID, err)
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := tlf.httpClient.readBody(resp.Body)
+	if err != nil {
+		return nil, NewPlaylistRequestFailed(playlistID, err)
+	}
+
+	var result map[string]interface{}
+	decodeErr := json.Unmarshal(bodyBytes, &result)
+	if decodeErr == nil {
+		if err := innertubeError(result, playlistID); err != nil {
+			return nil, asPlaylistError(err)
+		}
+	}
+	if err := raiseHTTPErrors(resp, playlistID); err != nil {
+		return nil, asPlaylistError(err)
+	}
+	if decodeErr != nil {
+		return nil, NewPlaylistRequestFailed(playlistID, decodeErr)
+	}
+	return result, nil
+}
+
+// ytInitialDataMarkers 页面中 ytInitialData 赋值语句的前缀
+var ytInitialDataMarkers = []string{"var ytInitialData = ", `window["ytInitialData"] = `, "ytInitialData = "}
+
+// extractYtInitialData 从页面中提取并解析 ytInitialData 对象
+func extractYtInitialData(html, id string) (map[string]interface{}, error) {
+	for _, marker := range ytInitialDataMarkers {
+		index := strings.Index(html, marker)
+		if index < 0 {
+			continue
+		}
+		// Decoder 只解析第一个 JSON 值，忽略后面的 ";</script>" 等内容
+		var data map[string]interface{}
+		if err := json.NewDecoder(strings.NewReader(html[index+len(marker):])).Decode(&data); err != nil {
+			return nil, NewYouTubeDataUnparsable(id)
+		}
+		return data, nil
+	}
+
+	if isCaptchaPage(html) {
+		return nil, NewIpBlocked(id)
+	}
+	return nil, NewYouTubeDataUnparsable(id)
+}
+
+// playlistPage 从播放列表页面或续页响应中解析出的内容
+type playlistPage struct {
+	// videoIDs 可播放条目的视频 ID
+	videoIDs []string
+	// continuation 下一页的续页令牌，没有下一页时为空
+	continuation string
+	// hasVideoList 数据中包含视频列表（用于区分空的播放列表和不存在的播放列表）
+	hasVideoList bool
+	// alert 页面上的提示信息，如 "The playlist does not exist."
+	alert string
+}
+
+// parsePlaylistPage 递归遍历 ytInitialData / browse 响应，收集 playlistVideoRenderer 条目和续页令牌
+// 不依赖完整的嵌套路径，以便在不同页面布局和续页响应（appendContinuationItemsAction）中通用
+func parsePlaylistPage(data map[string]interface{}) playlistPage {
+	var page playlistPage
+	var walk func(node interface{})
+	walk = func(node interface{}) {
+		switch value := node.(type) {
+		case []interface{}:
+			for _, item := range value {
+				walk(item)
+			}
+		case map[string]interface{}:
+			for key, child := range value {
+				switch key {
+				case "playlistVideoListRenderer", "appendContinuationItemsAction":
+					page.hasVideoList = true
+					walk(child)
+				case "playlistVideoRenderer":
+					renderer, _ := child.(map[string]interface{})
+					videoID, _ := renderer["videoId"].(string)
+					// 私享和已删除的视频 isPlayable 为 false
+					if playable, ok := renderer["isPlayable"].(bool); videoID != "" && (!ok || playable) {
+						page.videoIDs = append(page.videoIDs, videoID)
+					}
+				case "continuationItemRenderer":
+					if token := continuationToken(child); token != "" && page.continuation == "" {
+						page.continuation = token
+					}
+				case "alertRenderer", "alertWithButtonRenderer":
+					if page.alert == "" {
+						page.alert = rendererText(child)
+					}
+				default:
+					walk(child)
+				}
+			}
+		}
+	}
+	walk(data)
+	return page
+}
+
+// continuationToken 从 continuationItemRenderer 中提取续页令牌
+func continuationToken(renderer interface{}) string {
+	endpoint, _ := jsonPath(renderer, "continuationEndpoint").(map[string]interface{})
+	if token, ok := jsonPath(endpoint, "continuationCommand", "token").(string); ok {
+		return token
+	}
+	// 部分布局将 continuationCommand 包在 commandExecutorCommand 中
+	commands, _ := jsonPath(endpoint, "commandExecutorCommand", "commands").([]interface{})
+	for _, command := range commands {
+		if token, ok := jsonPath(command, "continuationCommand", "token").(string); ok {
+			return token
+		}
+	}
+	return ""
+}
+
+// rendererText 提取 renderer 中 text 字段的文本（simpleText 或 runs 拼接）
+func rendererText(renderer interface{}) string {
+	text, _ := jsonPath(renderer, "text").(map[string]interface{})
+	if simpleText, ok := text["simpleText"].(string); ok {
+		return simpleText
+	}
+	runs, _ := text["runs"].([]interface{})
+	var parts []string
+	for _, run := range runs {
+		if part, ok := jsonPath(run, "text").(string); ok {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// jsonPath 按键依次取嵌套的 JSON 对象字段，任意一层不存在时返回 nil
+func jsonPath(node interface{}, keys ...string) interface{} {
+	for _, key := range keys {
+		object, ok := node.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		node = object[key]
+	}
+	return node
+}
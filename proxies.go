@@ -2,7 +2,9 @@ package youtube_transcript_api
 
 import (
 	"fmt"
+	"hash/fnv"
 	"net/url"
+	"os"
 	"strings"
 )
 
@@ -21,7 +23,7 @@ type ProxyConfig interface {
 	ToProxyURLs() (httpURL, httpsURL string)
 	// PreventKeepingConnectionsAlive 是否阻止保持连接（用于轮换代理）
 	PreventKeepingConnectionsAlive() bool
-	// RetriesWhenBlocked 被阻止时的重试次数
+	// RetriesWhenBlocked 请求被阻止（RequestBlocked）时的最大尝试次数（包括第一次请求），小于等于 1 时不重试
 	RetriesWhenBlocked() int
 }
 
@@ -29,6 +31,8 @@ type ProxyConfig interface {
 type GenericProxyConfig struct {
 	HTTPURL  string
 	HTTPSURL string
+	// RetriesWhenBlockedCount 请求被阻止时的最大尝试次数，适用于自行轮换的代理池
+	RetriesWhenBlockedCount int
 }
 
 // NewGenericProxyConfig 创建通用代理配置
@@ -44,6 +48,22 @@ func NewGenericProxyConfig(httpURL, httpsURL string) (*GenericProxyConfig, error
 	}, nil
 }
 
+// NewGenericProxyConfigWithRetries 创建通用代理配置，并设置请求被阻止（RequestBlocked）时的最大尝试次数
+// 适用于代理本身会轮换出口 IP 的场景，重试时会重新请求视频页面
+func NewGenericProxyConfigWithRetries(httpURL, httpsURL string, retriesWhenBlocked int) (*GenericProxyConfig, error) {
+	if retriesWhenBlocked < 0 {
+		return nil, &InvalidProxyConfig{
+			Message: "retriesWhenBlocked must not be negative",
+		}
+	}
+	config, err := NewGenericProxyConfig(httpURL, httpsURL)
+	if err != nil {
+		return nil, err
+	}
+	config.RetriesWhenBlockedCount = retriesWhenBlocked
+	return config, nil
+}
+
 func (g *GenericProxyConfig) ToProxyURLs() (httpURL, httpsURL string) {
 	if g.HTTPURL != "" {
 		httpURL = g.HTTPURL
@@ -63,7 +83,7 @@ func (g *GenericProxyConfig) PreventKeepingConnectionsAlive() bool {
 }
 
 func (g *GenericProxyConfig) RetriesWhenBlocked() int {
-	return 0
+	return g.RetriesWhenBlockedCount
 }
 
 // WebshareProxyConfig Webshare 轮换住宅代理配置
@@ -80,6 +100,8 @@ type WebshareProxyConfig struct {
 const (
 	WebshareDefaultDomainName = "p.webshare.io"
 	WebshareDefaultPort       = 80
+	// WebshareDefaultRetriesWhenBlocked 命令行工具使用 Webshare 代理时默认的最大尝试次数
+	WebshareDefaultRetriesWhenBlocked = 10
 )
 
 // NewWebshareProxyConfig 创建 Webshare 代理配置
@@ -145,6 +167,127 @@ func (w *WebshareProxyConfig) RetriesWhenBlocked() int {
 	return w.RetriesWhenBlockedCount
 }
 
+// PerVideoProxyConfig 可以按视频选择代理的代理配置
+// SetupHTTPClientProxy 会将 ProxyURLForVideo 设为 HTTPClient.VideoProxy：带有视频 ID 的请求使用它返回的代理，
+// 其他请求（如播放列表页面）使用 ToProxyURLs 返回的代理
+type PerVideoProxyConfig interface {
+	ProxyConfig
+	// ProxyURLForVideo 返回 videoID 的所有请求使用的代理 URL
+	ProxyURLForVideo(videoID string) string
+}
+
+// HashedProxyPool 按视频 ID 的哈希从代理池中选择代理：同一个视频的所有请求（视频页面、InnerTube、字幕内容，
+// 以及被阻止后的重试）总是经过同一个出口，便于复现问题、与基于 IP 的会话保持一致，也能复用到该代理的连接。
+// 与每次请求轮换出口（如 WebshareProxyConfig）相比，代价是某个出口被 YouTube 阻止时，落在它上面的视频会持续失败，
+// 重试也无法换到其他出口；负载按视频而不是按请求分摊，单个视频请求量大时无法分散到多个出口。
+// 使用 rendezvous 哈希（最高随机权重），增删代理时只有落在变化的代理上的视频会换到其他代理
+type HashedProxyPool struct {
+	ProxyURLs []string
+	// RetriesWhenBlockedCount 请求被阻止时的最大尝试次数（重试仍使用同一个代理）
+	RetriesWhenBlockedCount int
+}
+
+// NewHashedProxyPool 创建按视频 ID 哈希选择代理的代理池，proxyURLs 为空或包含无效 URL 时返回 InvalidProxyConfig
+func NewHashedProxyPool(proxyURLs []string) (*HashedProxyPool, error) {
+	if len(proxyURLs) == 0 {
+		return nil, &InvalidProxyConfig{
+			Message: "HashedProxyPool requires at least one proxy URL",
+		}
+	}
+	for _, proxyURL := range proxyURLs {
+		if u, err := url.Parse(proxyURL); err != nil || u.Host == "" {
+			return nil, &InvalidProxyConfig{
+				Message: fmt.Sprintf("invalid proxy URL in HashedProxyPool: %q", proxyURL),
+			}
+		}
+	}
+	return &HashedProxyPool{
+		ProxyURLs: append([]string(nil), proxyURLs...),
+	}, nil
+}
+
+// ProxyURLForVideo 返回 videoID 对应的代理 URL，相同的视频 ID 和代理池总是得到相同的结果
+func (p *HashedProxyPool) ProxyURLForVideo(videoID string) string {
+	best := ""
+	var bestWeight uint64
+	for _, proxyURL := range p.ProxyURLs {
+		hash := fnv.New64a()
+		hash.Write([]byte(videoID))
+		hash.Write([]byte{0})
+		hash.Write([]byte(proxyURL))
+		if weight := hash.Sum64(); best == "" || weight > bestWeight {
+			best, bestWeight = proxyURL, weight
+		}
+	}
+	return best
+}
+
+// ToProxyURLs 返回池中的第一个代理，用于不属于某个视频的请求
+func (p *HashedProxyPool) ToProxyURLs() (httpURL, httpsURL string) {
+	if len(p.ProxyURLs) == 0 {
+		return "", ""
+	}
+	return p.ProxyURLs[0], p.ProxyURLs[0]
+}
+
+func (p *HashedProxyPool) PreventKeepingConnectionsAlive() bool {
+	return false
+}
+
+func (p *HashedProxyPool) RetriesWhenBlocked() int {
+	return p.RetriesWhenBlockedCount
+}
+
+// ProxyConfigFromEnv 根据标准环境变量 HTTP_PROXY / HTTPS_PROXY（及小写形式）创建 GenericProxyConfig
+// 未设置任何代理变量，或 NO_PROXY 覆盖了 youtube.com 时返回 nil。
+// 环境变量不会被隐式读取：只有将返回值传给 NewYouTubeTranscriptApi 时才生效，
+// 因此显式传入的代理配置总是优先（CLI 中命令行参数优先于环境变量）
+func ProxyConfigFromEnv() ProxyConfig {
+	httpURL := getenvAny("HTTP_PROXY", "http_proxy")
+	httpsURL := getenvAny("HTTPS_PROXY", "https_proxy")
+	if httpURL == "" && httpsURL == "" {
+		return nil
+	}
+
+	if noProxyMatches(getenvAny("NO_PROXY", "no_proxy"), "www.youtube.com") {
+		return nil
+	}
+
+	proxyConfig, err := NewGenericProxyConfig(httpURL, httpsURL)
+	if err != nil {
+		return nil
+	}
+	return proxyConfig
+}
+
+func getenvAny(names ...string) string {
+	for _, name := range names {
+		if value := strings.TrimSpace(os.Getenv(name)); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// noProxyMatches 判断 host 是否被 NO_PROXY 列表覆盖（支持 "*"、"youtube.com"、".youtube.com"）
+func noProxyMatches(noProxy, host string) bool {
+	for _, entry := range strings.Split(noProxy, ",") {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		if entry == "*" {
+			return true
+		}
+		entry = strings.TrimPrefix(entry, "*")
+		entry = strings.TrimPrefix(entry, ".")
+		if host == entry || strings.HasSuffix(host, "."+entry) {
+			return true
+		}
+	}
+	return false
+}
+
 // SetupHTTPClientProxy 为 HTTP 客户端设置代理
 func SetupHTTPClientProxy(client *HTTPClient, proxyConfig ProxyConfig) error {
 	if proxyConfig == nil {
@@ -170,10 +313,27 @@ func SetupHTTPClientProxy(client *HTTPClient, proxyConfig ProxyConfig) error {
 		client.HTTPSProxy = httpsProxyURL
 	}
 
+	if perVideo, ok := proxyConfig.(PerVideoProxyConfig); ok {
+		client.VideoProxy = func(videoID string) *url.URL {
+			proxyURL := perVideo.ProxyURLForVideo(videoID)
+			if proxyURL == "" {
+				return nil
+			}
+			parsed, err := url.Parse(proxyURL)
+			if err != nil {
+				return nil
+			}
+			return parsed
+		}
+	}
+
 	// 如果配置要求阻止保持连接，设置 Connection: close 头
 	if proxyConfig.PreventKeepingConnectionsAlive() {
 		client.Headers["Connection"] = "close"
 	}
 
+	// 代理变化后需要重建 Transport
+	client.resetTransport()
+
 	return nil
 }
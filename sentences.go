@@ -0,0 +1,99 @@
+package youtube_transcript_api
+
+import (
+	"strings"
+)
+
+// Sentence 由一个或多个字幕片段合并后按句末标点切分得到的句子
+type Sentence struct {
+	Text string
+	// Start、End 句子的开始和结束时间（秒），分别取组成该句子的第一个片段的开始和最后一个片段的结束
+	Start float64
+	End   float64
+}
+
+// sentenceAbbreviations 以 "." 结尾但通常不表示句子结束的缩写（小写）
+var sentenceAbbreviations = map[string]bool{
+	"mr.": true, "mrs.": true, "ms.": true, "dr.": true, "prof.": true, "sr.": true, "jr.": true, "st.": true,
+	"vs.": true, "e.g.": true, "i.e.": true, "approx.": true, "no.": true, "fig.": true,
+	"inc.": true, "ltd.": true, "co.": true, "u.s.": true, "u.k.": true,
+}
+
+// Sentences 合并字幕片段并按句末标点（.、!、?）切分为句子，每个句子的时间覆盖组成它的所有片段
+// 常见缩写（如 "Mr."、"e.g."）和单个大写字母的首字母缩写（如 "J."）不会被当作句子结束。
+// 字幕中完全没有这些标点时（如中文、日文等不使用拉丁标点的语言），每个片段作为一个句子返回
+func (ft *FetchedTranscript) Sentences() []Sentence {
+	if !hasSentenceEndings(ft.Snippets) {
+		var sentences []Sentence
+		for _, snippet := range ft.Snippets {
+			text := strings.TrimSpace(snippet.Text)
+			if text == "" {
+				continue
+			}
+			sentences = append(sentences, Sentence{Text: text, Start: snippet.Start, End: snippet.Start + snippet.Duration})
+		}
+		return sentences
+	}
+
+	var sentences []Sentence
+	var words []string
+	var current Sentence
+	for _, snippet := range ft.Snippets {
+		for _, word := range strings.Fields(snippet.Text) {
+			if len(words) == 0 {
+				current.Start = snippet.Start
+			}
+			words = append(words, word)
+			current.End = snippet.Start + snippet.Duration
+
+			if endsSentence(word) {
+				current.Text = strings.Join(words, " ")
+				sentences = append(sentences, current)
+				words, current = nil, Sentence{}
+			}
+		}
+	}
+
+	// 最后一句没有句末标点
+	if len(words) > 0 {
+		current.Text = strings.Join(words, " ")
+		sentences = append(sentences, current)
+	}
+	return sentences
+}
+
+// hasSentenceEndings 检查是否有任意片段中的词以句末标点结尾
+func hasSentenceEndings(snippets []FetchedTranscriptSnippet) bool {
+	for _, snippet := range snippets {
+		for _, word := range strings.Fields(snippet.Text) {
+			if endsSentence(word) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// endsSentence 判断词是否以句末标点结尾（允许后面跟引号或右括号），并排除缩写
+func endsSentence(word string) bool {
+	trimmed := strings.TrimRight(word, `"')]”’`)
+	if trimmed == "" {
+		return false
+	}
+
+	switch trimmed[len(trimmed)-1] {
+	case '!', '?':
+		return true
+	case '.':
+		lower := strings.ToLower(strings.TrimLeft(trimmed, `"'([“‘`))
+		if sentenceAbbreviations[lower] {
+			return false
+		}
+		// 单个大写字母的首字母缩写，如 "J. R. R. Tolkien"
+		if len(lower) == 2 && trimmed[len(trimmed)-2] >= 'A' && trimmed[len(trimmed)-2] <= 'Z' {
+			return false
+		}
+		return true
+	}
+	return false
+}
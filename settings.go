@@ -5,8 +5,16 @@ const (
 	WatchURLTemplate        = "https://www.youtube.com/watch?v=%s"
 	InnertubeAPIURLTemplate = "https://www.youtube.com/youtubei/v1/player?key=%s"
 	ThumbnailURLTemplate    = "https://img.youtube.com/vi/%s/default.jpg"
+	EmbedURLTemplate        = "https://www.youtube.com/embed/%s"
+
+	// 播放列表页面和 InnerTube browse 接口（用于获取播放列表的后续分页）
+	PlaylistURLTemplate        = "https://www.youtube.com/playlist?list=%s"
+	InnertubeBrowseURLTemplate = "https://www.youtube.com/youtubei/v1/browse?key=%s"
 )
 
+// DefaultLanguages 未指定语言时使用的默认语言列表（可通过 WithDefaultLanguages 为单个实例覆盖）
+var DefaultLanguages = []string{"en"}
+
 // InnertubeContext 是调用 YouTube InnerTube API 时使用的客户端上下文
 var InnertubeContext = map[string]interface{}{
 	"context": map[string]interface{}{
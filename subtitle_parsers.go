@@ -0,0 +1,159 @@
+package youtube_transcript_api
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ParseSRT 解析 SRT 字幕文件，返回只包含字幕片段的 FetchedTranscript（标题、语言等元数据需要调用方自行设置）
+// 多行字幕的各行会以 "\n" 连接
+func ParseSRT(r io.Reader) (*FetchedTranscript, error) {
+	blocks, err := readSubtitleBlocks(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var snippets []FetchedTranscriptSnippet
+	for _, block := range blocks {
+		// 序号行可选
+		if !strings.Contains(block[0], "-->") {
+			block = block[1:]
+		}
+		if len(block) == 0 {
+			continue
+		}
+		snippet, err := parseCue(block, ",")
+		if err != nil {
+			return nil, err
+		}
+		snippets = append(snippets, snippet)
+	}
+
+	return &FetchedTranscript{Snippets: snippets}, nil
+}
+
+// ParseWebVTT 解析 WebVTT 字幕文件，返回只包含字幕片段的 FetchedTranscript
+// WEBVTT 头、NOTE / STYLE / REGION 块会被跳过，字幕标识行和时间行后的字幕设置会被忽略，多行字幕的各行以 "\n" 连接
+func ParseWebVTT(r io.Reader) (*FetchedTranscript, error) {
+	blocks, err := readSubtitleBlocks(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(blocks) == 0 || !strings.HasPrefix(blocks[0][0], "WEBVTT") {
+		return nil, fmt.Errorf("invalid WebVTT: missing WEBVTT header")
+	}
+
+	var snippets []FetchedTranscriptSnippet
+	for _, block := range blocks[1:] {
+		switch keyword := strings.Fields(block[0])[0]; keyword {
+		case "NOTE", "STYLE", "REGION":
+			continue
+		}
+		// 字幕标识行可选
+		if !strings.Contains(block[0], "-->") {
+			block = block[1:]
+		}
+		if len(block) == 0 {
+			continue
+		}
+		snippet, err := parseCue(block, ".")
+		if err != nil {
+			return nil, err
+		}
+		snippets = append(snippets, snippet)
+	}
+
+	return &FetchedTranscript{Snippets: snippets}, nil
+}
+
+// readSubtitleBlocks 按空行将字幕文件切分为块，去除 BOM 和行尾的 "\r"
+func readSubtitleBlocks(r io.Reader) ([][]string, error) {
+	var blocks [][]string
+	var current []string
+
+	scanner := bufio.NewScanner(r)
+	first := true
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if first {
+			line = strings.TrimPrefix(line, "\ufeff")
+			first = false
+		}
+		if strings.TrimSpace(line) == "" {
+			if len(current) > 0 {
+				blocks = append(blocks, current)
+				current = nil
+			}
+			continue
+		}
+		current = append(current, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(current) > 0 {
+		blocks = append(blocks, current)
+	}
+	return blocks, nil
+}
+
+// parseCue 解析以时间行开头的字幕块，msSeparator 为毫秒分隔符（SRT 为 ","，WebVTT 为 "."）
+func parseCue(block []string, msSeparator string) (FetchedTranscriptSnippet, error) {
+	parts := strings.SplitN(block[0], "-->", 2)
+	if len(parts) != 2 {
+		return FetchedTranscriptSnippet{}, fmt.Errorf("invalid cue timing line: %q", block[0])
+	}
+
+	endFields := strings.Fields(parts[1])
+	if len(endFields) == 0 {
+		return FetchedTranscriptSnippet{}, fmt.Errorf("invalid cue timing line: %q", block[0])
+	}
+
+	start, err := parseCueTimestamp(strings.TrimSpace(parts[0]), msSeparator)
+	if err != nil {
+		return FetchedTranscriptSnippet{}, err
+	}
+	end, err := parseCueTimestamp(endFields[0], msSeparator)
+	if err != nil {
+		return FetchedTranscriptSnippet{}, err
+	}
+
+	return FetchedTranscriptSnippet{
+		Text:     strings.Join(block[1:], "\n"),
+		Start:    start,
+		Duration: end - start,
+	}, nil
+}
+
+// parseCueTimestamp 解析 [HH:]MM:SS<sep>mmm 格式的时间戳，返回秒数
+func parseCueTimestamp(timestamp, msSeparator string) (float64, error) {
+	invalid := fmt.Errorf("invalid cue timestamp: %q", timestamp)
+
+	clock, millis := timestamp, "0"
+	if i := strings.LastIndex(timestamp, msSeparator); i >= 0 {
+		clock, millis = timestamp[:i], timestamp[i+1:]
+	}
+
+	fields := strings.Split(clock, ":")
+	if len(fields) < 2 || len(fields) > 3 {
+		return 0, invalid
+	}
+
+	var seconds float64
+	for _, field := range fields {
+		value, err := strconv.Atoi(field)
+		if err != nil || value < 0 {
+			return 0, invalid
+		}
+		seconds = seconds*60 + float64(value)
+	}
+
+	ms, err := strconv.Atoi(millis)
+	if err != nil || ms < 0 {
+		return 0, invalid
+	}
+	return seconds + float64(ms)/1000, nil
+}
@@ -1,15 +1,18 @@
 package youtube_transcript_api
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"html"
-	"io"
 	"net/http"
 	"net/url"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/beevik/etree"
 )
@@ -30,6 +33,11 @@ type FetchedTranscript struct {
 	Language     string // 字幕语言
 	LanguageCode string // 字幕语言代码
 	IsGenerated  bool   // 是否是自动生成的字幕
+	// SourceLanguageCode、SourceLanguage 翻译得到的字幕的源字幕语言代码和名称，非翻译字幕为空
+	SourceLanguageCode string
+	SourceLanguage     string
+	// Chapters 视频章节（从视频简介解析），视频没有章节时为空
+	Chapters []Chapter
 }
 
 // ToRawData 转换为原始数据格式（用于 JSON 序列化）
@@ -45,6 +53,26 @@ func (ft *FetchedTranscript) ToRawData() []map[string]interface{} {
 	return result
 }
 
+// RawDataOptions 控制 ToRawDataWith 额外输出的字段
+type RawDataOptions struct {
+	IncludeIndex bool // 输出片段序号 "index"（从 0 开始，按当前顺序编号）
+	IncludeEnd   bool // 输出结束时间 "end"（与 SRT/WebVTT 一致，与下一个片段重叠时截断到其开始时间）
+}
+
+// ToRawDataWith 与 ToRawData 相同，可按 opts 额外输出 index 和 end 字段
+func (ft *FetchedTranscript) ToRawDataWith(opts RawDataOptions) []map[string]interface{} {
+	result := ft.ToRawData()
+	for i := range result {
+		if opts.IncludeIndex {
+			result[i]["index"] = i
+		}
+		if opts.IncludeEnd {
+			result[i]["end"] = snippetEnd(ft.Snippets, i)
+		}
+	}
+	return result
+}
+
 // TranslationLanguage 表示可翻译的语言
 type TranslationLanguage struct {
 	Language     string
@@ -63,8 +91,36 @@ type Transcript struct {
 	IsGenerated             bool
 	TranslationLanguages    []TranslationLanguage
 	translationLanguagesMap map[string]string
+	// IsDefault 该轨道是 YouTube 播放器默认显示的字幕（默认音轨的 defaultCaptionTrackIndex），参见 TranscriptList.DefaultTranscript
+	IsDefault bool
+	// SourceLanguageCode、SourceLanguage 由 Translate 得到的字幕的源字幕语言代码和名称，非翻译字幕为空
+	SourceLanguageCode string
+	SourceLanguage     string
+	// chapters 视频章节，获取字幕时复制到 FetchedTranscript.Chapters
+	chapters []Chapter
+	// refreshURL 重新获取该字幕轨道的最新 URL，由 WithRefreshOnForbidden 设置
+	refreshURL func(ctx context.Context) (string, error)
+	// captionOptions 获取字幕内容时使用的解析器、缓存和空响应体重试设置，由获取列表（或 Bind）的实例决定
+	captionOptions captionOptions
+}
+
+// DefaultEmptyBodyRetries 字幕响应体为空时的默认重试次数
+const DefaultEmptyBodyRetries = 2
+
+// captionOptions 获取字幕内容时使用的设置，参见 WithTranscriptContentParser、WithMinSnippetDuration、
+// WithCaptionCache 和 WithEmptyBodyRetries
+type captionOptions struct {
+	// parser 为 nil 时使用 DefaultTranscriptContentParser
+	parser             TranscriptContentParser
+	minSnippetDuration float64
+	// cache 为 nil 时不缓存
+	cache            CaptionCache
+	emptyBodyRetries int
 }
 
+// defaultCaptionOptions 没有设置任何 Option 时获取字幕内容的设置
+var defaultCaptionOptions = captionOptions{emptyBodyRetries: DefaultEmptyBodyRetries}
+
 // NewTranscript 创建新的 Transcript 对象
 func NewTranscript(
 	httpClient *HTTPClient,
@@ -93,54 +149,201 @@ func NewTranscript(
 		IsGenerated:             isGenerated,
 		TranslationLanguages:    translationLanguages,
 		translationLanguagesMap: translationMap,
+		captionOptions:          defaultCaptionOptions,
 	}
 }
 
-// IsTranslatable 检查是否可翻译
+// Bind 让字幕（以及之后由它 Translate 得到的字幕）改用 api 的 HTTP 客户端和字幕获取设置（解析器、缓存等）获取内容，返回 t 本身
+// 用于先 List 展示语言、之后再获取内容的场景：获取列表的实例已经 Close 或希望使用其他实例（如不同的代理）时调用。
+// WithRefreshOnForbidden 的 URL 刷新仍通过获取列表的实例进行
+func (t *Transcript) Bind(api *YouTubeTranscriptApi) *Transcript {
+	t.httpClient = api.fetcher.httpClient
+	t.captionOptions = api.options.captionOptions()
+	return t
+}
+
+// IsTranslatable 检查是否可翻译（对应轨道的 isTranslatable），不可翻译的轨道没有 TranslationLanguages
 func (t *Transcript) IsTranslatable() bool {
 	return len(t.TranslationLanguages) > 0
 }
 
 // Fetch 获取实际字幕内容
 func (t *Transcript) Fetch(preserveFormatting bool) (*FetchedTranscript, error) {
-	if strings.Contains(t.url, "&exp=xpe") {
-		return nil, NewPoTokenRequired(t.VideoID)
-	}
+	return t.FetchContext(context.Background(), preserveFormatting)
+}
 
-	resp, err := t.httpClient.Get(t.url)
-	if err != nil {
-		return nil, NewYouTubeRequestFailed(t.VideoID, err)
+// FetchContext 获取实际字幕内容，ctx 取消时中止请求
+// 字幕使用获取它的 YouTubeTranscriptApi 实例的 HTTP 客户端，在实例的整个生命周期内（包括 Reset 之后）都可以获取；
+// 实例 Close 之后返回 ErrClientClosed，可以通过 Bind 改用其他实例获取
+func (t *Transcript) FetchContext(ctx context.Context, preserveFormatting bool) (*FetchedTranscript, error) {
+	if t.httpClient == nil || t.httpClient.isClosed() {
+		return nil, ErrClientClosed
 	}
-	defer resp.Body.Close()
-
-	if err := raiseHTTPErrors(resp, t.VideoID); err != nil {
+	snippets, err := fetchTranscriptSnippets(ctx, t.httpClient, t.captionOptions, t.url, t.VideoID, preserveFormatting)
+	if isForbidden(err) && t.refreshURL != nil {
+		// 字幕 URL 的签名可能已过期：获取新的 URL 后重试一次，失败时保留原始错误
+		if freshURL, refreshErr := t.refreshURL(ctx); refreshErr == nil {
+			t.url = freshURL
+			snippets, err = fetchTranscriptSnippets(ctx, t.httpClient, t.captionOptions, t.url, t.VideoID, preserveFormatting)
+		}
+	}
+	if err != nil {
 		return nil, err
 	}
 
-	// 读取响应体
-	bodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, NewYouTubeRequestFailed(t.VideoID, err)
+	return t.fetched(snippets), nil
+}
+
+// fetched 使用 t 的元数据和 snippets 创建 FetchedTranscript
+func (t *Transcript) fetched(snippets []FetchedTranscriptSnippet) *FetchedTranscript {
+	return &FetchedTranscript{
+		Title:              t.Title,
+		ThumbnailURL:       t.ThumbnailURL,
+		Snippets:           snippets,
+		VideoID:            t.VideoID,
+		Language:           t.Language,
+		LanguageCode:       t.LanguageCode,
+		IsGenerated:        t.IsGenerated,
+		SourceLanguageCode: t.SourceLanguageCode,
+		SourceLanguage:     t.SourceLanguage,
+		Chapters:           t.chapters,
 	}
-	body := string(bodyBytes)
+}
 
-	parser := NewTranscriptParser(preserveFormatting)
-	snippets, err := parser.Parse(body)
+// FetchTranscriptByURL 直接通过字幕 URL（timedtext）获取字幕，跳过字幕列表的构建
+// 适用于已自行解析 player response 并拿到字幕 URL 的场景。
+// 注意：返回结果中只有 VideoID 和 LanguageCode 会被填充，标题、语言名称等元数据需要调用方自行设置。
+// 使用默认的字幕获取设置（内置解析器、不缓存、DefaultEmptyBodyRetries）；client 为 nil 或已经 Close 时返回 ErrClientClosed
+func FetchTranscriptByURL(ctx context.Context, client *HTTPClient, captionURL, videoID, languageCode string, preserveFormatting bool) (*FetchedTranscript, error) {
+	if client == nil || client.isClosed() {
+		return nil, ErrClientClosed
+	}
+	snippets, err := fetchTranscriptSnippets(ctx, client, defaultCaptionOptions, captionURL, videoID, preserveFormatting)
 	if err != nil {
-		return nil, NewYouTubeRequestFailed(t.VideoID, err)
+		return nil, err
 	}
 
 	return &FetchedTranscript{
-		Title:        t.Title,
-		ThumbnailURL: t.ThumbnailURL,
 		Snippets:     snippets,
-		VideoID:      t.VideoID,
-		Language:     t.Language,
-		LanguageCode: t.LanguageCode,
-		IsGenerated:  t.IsGenerated,
+		VideoID:      videoID,
+		LanguageCode: languageCode,
 	}, nil
 }
 
+// isForbidden 判断错误是否为 HTTP 403 响应
+func isForbidden(err error) bool {
+	requestFailed, ok := err.(*YouTubeRequestFailed)
+	return ok && requestFailed.StatusCode == http.StatusForbidden
+}
+
+// fetchTranscriptSnippets 请求字幕 URL 并按 opts 解析字幕片段
+func fetchTranscriptSnippets(ctx context.Context, client *HTTPClient, opts captionOptions, captionURL, videoID string, preserveFormatting bool) ([]FetchedTranscriptSnippet, error) {
+	ctx = withRequestVideoID(ctx, videoID)
+	if strings.Contains(captionURL, "&exp=xpe") {
+		return nil, NewPoTokenRequired(videoID)
+	}
+
+	var body string
+	cached := false
+	if opts.cache != nil {
+		if data, ok := opts.cache.Get(captionURL); ok {
+			body, cached = string(data), true
+		}
+	}
+
+	if !cached {
+		var err error
+		body, err = fetchNonEmptyCaptionBody(ctx, client, captionURL, videoID, opts.emptyBodyRetries)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	parser := opts.parser
+	if parser == nil {
+		parser = DefaultTranscriptContentParser
+	}
+	snippets, err := parser.Parse([]byte(body), preserveFormatting)
+	if err := captionBodyError(body, videoID, err); err != nil {
+		return nil, err
+	}
+
+	// 缓存原始响应体，之后按各次调用的 preserveFormatting 重新解析
+	if opts.cache != nil && !cached {
+		opts.cache.Set(captionURL, []byte(body))
+	}
+
+	return dropShortSnippets(snippets, opts.minSnippetDuration), nil
+}
+
+// captionDataPrefixes 字幕响应体的开头（XML 字幕，以及自定义解析器处理的 json3 / WebVTT）
+var captionDataPrefixes = []string{"<?xml", "<transcript", "<timedtext", "{", "WEBVTT"}
+
+// looksLikeCaptionData 判断响应体是否以字幕数据的格式开头
+func looksLikeCaptionData(body string) bool {
+	body = strings.TrimLeft(strings.TrimPrefix(body, "\uFEFF"), " \t\r\n")
+	for _, prefix := range captionDataPrefixes {
+		if strings.HasPrefix(body, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// captionBodyError 根据解析结果检查字幕响应体：被限流时 YouTube 会返回验证码页面而不是字幕，此时返回 IpBlocked；
+// 只在响应体不像字幕数据或解析失败时检查验证码特征，避免字幕正文中恰好出现 "unusual traffic ..." 等文字时被误判。
+// 解析失败的其他情况返回 YouTubeRequestFailed
+func captionBodyError(body, videoID string, parseErr error) error {
+	if (parseErr != nil || !looksLikeCaptionData(body)) && isCaptchaPage(body) {
+		return NewIpBlocked(videoID)
+	}
+	if parseErr != nil {
+		return NewYouTubeRequestFailed(videoID, parseErr)
+	}
+	return nil
+}
+
+// fetchNonEmptyCaptionBody 请求字幕响应体，响应体为空时最多重试 retries 次
+// YouTube 缓存偶尔会返回 200 但响应体为空，重试通常即可成功；
+// 合法但没有任何字幕的文档（有根节点、没有 <text>）不会重试
+func fetchNonEmptyCaptionBody(ctx context.Context, client *HTTPClient, captionURL, videoID string, retries int) (string, error) {
+	start := time.Now()
+	for attempt := 0; ; attempt++ {
+		body, err := fetchCaptionBody(ctx, client, captionURL, videoID)
+		if err != nil {
+			return "", err
+		}
+		if strings.TrimSpace(body) != "" {
+			return body, nil
+		}
+		if attempt >= retries || !client.retryBudgetAllows(start, attempt) {
+			return "", NewYouTubeRequestFailed(videoID, errors.New("empty caption response body"))
+		}
+		if err := client.sleepBackoff(ctx, attempt); err != nil {
+			return "", NewYouTubeRequestFailed(videoID, err)
+		}
+	}
+}
+
+// fetchCaptionBody 请求字幕 URL 并返回响应体
+func fetchCaptionBody(ctx context.Context, client *HTTPClient, captionURL, videoID string) (string, error) {
+	resp, err := client.GetContext(ctx, captionURL)
+	if err != nil {
+		return "", NewYouTubeRequestFailed(videoID, err)
+	}
+	defer resp.Body.Close()
+
+	if err := raiseHTTPErrors(resp, videoID); err != nil {
+		return "", err
+	}
+
+	bodyBytes, err := client.readBody(resp.Body)
+	if err != nil {
+		return "", NewYouTubeRequestFailed(videoID, err)
+	}
+	return string(bodyBytes), nil
+}
+
 // Translate 翻译到指定语言
 func (t *Transcript) Translate(languageCode string) (*Transcript, error) {
 	if !t.IsTranslatable() {
@@ -152,10 +355,10 @@ func (t *Transcript) Translate(languageCode string) (*Transcript, error) {
 		return nil, NewTranslationLanguageNotAvailable(t.VideoID)
 	}
 
-	// 构建翻译后的 URL
-	translatedURL := fmt.Sprintf("%s&tlang=%s", t.url, languageCode)
+	// 构建翻译后的 URL（保留原字幕轨道的所有参数，如 kind=asr）
+	translatedURL := withTranslationLanguage(t.url, languageCode)
 
-	return NewTranscript(
+	translated := NewTranscript(
 		t.httpClient,
 		t.VideoID,
 		t.Title,
@@ -165,7 +368,82 @@ func (t *Transcript) Translate(languageCode string) (*Transcript, error) {
 		languageCode,
 		true,                    // 翻译后的字幕标记为自动生成
 		[]TranslationLanguage{}, // 翻译后的字幕不能再翻译
-	), nil
+	)
+	translated.SourceLanguageCode = t.LanguageCode
+	translated.SourceLanguage = t.Language
+	translated.chapters = t.chapters
+	translated.captionOptions = t.captionOptions
+	if refreshURL := t.refreshURL; refreshURL != nil {
+		translated.refreshURL = func(ctx context.Context) (string, error) {
+			freshURL, err := refreshURL(ctx)
+			if err != nil {
+				return "", err
+			}
+			return withTranslationLanguage(freshURL, languageCode), nil
+		}
+	}
+	return translated, nil
+}
+
+// withTranslationLanguage 在字幕 URL 上设置 tlang 参数
+// 原有的查询参数保持原样（不重新编码，避免改变签名参数），已存在的 tlang 会被替换
+func withTranslationLanguage(captionURL, languageCode string) string {
+	base, rawQuery := captionURL, ""
+	if i := strings.Index(captionURL, "?"); i >= 0 {
+		base, rawQuery = captionURL[:i], captionURL[i+1:]
+	}
+
+	var params []string
+	for _, param := range strings.Split(rawQuery, "&") {
+		if param == "" || param == "tlang" || strings.HasPrefix(param, "tlang=") {
+			continue
+		}
+		params = append(params, param)
+	}
+	params = append(params, "tlang="+url.QueryEscape(languageCode))
+
+	return base + "?" + strings.Join(params, "&")
+}
+
+// DisplayLabel 返回适合在界面上展示的字幕标签，如 "English (auto-generated, translated from Japanese)"
+// 语言名称优先使用内置语言名称表，找不到时使用 YouTube 提供的名称
+func (t *Transcript) DisplayLabel() string {
+	return displayLabel(t.LanguageCode, t.Language, t.IsGenerated, t.SourceLanguageCode, t.SourceLanguage)
+}
+
+// DisplayLabel 与 Transcript.DisplayLabel 相同
+func (ft *FetchedTranscript) DisplayLabel() string {
+	return displayLabel(ft.LanguageCode, ft.Language, ft.IsGenerated, ft.SourceLanguageCode, ft.SourceLanguage)
+}
+
+// generatedLanguageSuffix YouTube 为自动生成字幕的语言名称添加的后缀
+const generatedLanguageSuffix = " (auto-generated)"
+
+func displayLabel(languageCode, language string, isGenerated bool, sourceLanguageCode, sourceLanguage string) string {
+	label := displayLanguageName(languageCode, language)
+
+	var qualifiers []string
+	if isGenerated {
+		qualifiers = append(qualifiers, "auto-generated")
+	}
+	if sourceLanguageCode != "" {
+		qualifiers = append(qualifiers, "translated from "+displayLanguageName(sourceLanguageCode, sourceLanguage))
+	}
+	if len(qualifiers) > 0 {
+		label += " (" + strings.Join(qualifiers, ", ") + ")"
+	}
+	return label
+}
+
+// displayLanguageName 返回语言名称，不包含 YouTube 添加的 "(auto-generated)" 后缀
+func displayLanguageName(languageCode, language string) string {
+	if name, ok := LanguageName(languageCode); ok {
+		return name
+	}
+	if name := strings.TrimSuffix(language, generatedLanguageSuffix); name != "" {
+		return name
+	}
+	return languageCode
 }
 
 // String 返回字符串表示
@@ -177,6 +455,28 @@ func (t *Transcript) String() string {
 	return fmt.Sprintf(`%s ("%s")%s`, t.LanguageCode, t.Language, translationDesc)
 }
 
+// isASRCaptionTrack 判断字幕轨道是否为自动生成
+// 不同客户端版本的返回格式不同：有的直接给出 kind="asr"，有的只有 vssId（自动生成的为 "a.en" 形式）
+func isASRCaptionTrack(captionMap map[string]interface{}) bool {
+	if kind, ok := captionMap["kind"].(string); ok && kind == "asr" {
+		return true
+	}
+	vssID, _ := captionMap["vssId"].(string)
+	return strings.HasPrefix(vssID, "a.")
+}
+
+// jsonBool 将 JSON 中的布尔值或字符串 "true" 转换为 bool
+func jsonBool(value interface{}) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case string:
+		return strings.EqualFold(v, "true")
+	default:
+		return false
+	}
+}
+
 // TranscriptList 表示某个视频的所有可用字幕列表
 type TranscriptList struct {
 	VideoID                    string
@@ -200,8 +500,74 @@ func NewTranscriptList(
 	}
 }
 
+// Bind 将列表中的所有字幕绑定到 api，参见 Transcript.Bind，返回 tl 本身
+func (tl *TranscriptList) Bind(api *YouTubeTranscriptApi) *TranscriptList {
+	for _, transcript := range tl.manuallyCreatedTranscripts {
+		transcript.Bind(api)
+	}
+	for _, transcript := range tl.generatedTranscripts {
+		transcript.Bind(api)
+	}
+	return tl
+}
+
+// setCaptionOptions 为列表中的每个字幕设置获取内容时使用的 captionOptions
+func (tl *TranscriptList) setCaptionOptions(opts captionOptions) {
+	for _, transcript := range tl.manuallyCreatedTranscripts {
+		transcript.captionOptions = opts
+	}
+	for _, transcript := range tl.generatedTranscripts {
+		transcript.captionOptions = opts
+	}
+}
+
+// bindURLRefresh 为列表中的每个字幕设置 refreshURL：通过 relist 重新获取字幕列表，返回同一语言、同一类型字幕的新 URL
+func (tl *TranscriptList) bindURLRefresh(relist func(ctx context.Context) (*TranscriptList, error)) {
+	bind := func(transcripts map[string]*Transcript, generated bool) {
+		for languageCode, transcript := range transcripts {
+			languageCode := languageCode
+			transcript.refreshURL = func(ctx context.Context) (string, error) {
+				fresh, err := relist(ctx)
+				if err != nil {
+					return "", err
+				}
+				freshTranscripts := fresh.manuallyCreatedTranscripts
+				if generated {
+					freshTranscripts = fresh.generatedTranscripts
+				}
+				freshTranscript, ok := freshTranscripts[languageCode]
+				if !ok {
+					return "", NewNoTranscriptFound(tl.VideoID, []string{languageCode}, fresh)
+				}
+				return freshTranscript.url, nil
+			}
+		}
+	}
+	bind(tl.manuallyCreatedTranscripts, false)
+	bind(tl.generatedTranscripts, true)
+}
+
+// defaultCaptionTrackIndex 返回默认音轨（defaultAudioTrackIndex，缺失时为第一个音轨）的 defaultCaptionTrackIndex，
+// 即播放器默认显示的字幕在 captionTracks 中的下标，没有时返回 -1
+func defaultCaptionTrackIndex(captionsJSON map[string]interface{}) int {
+	audioTracks, _ := captionsJSON["audioTracks"].([]interface{})
+	audioIndex := 0
+	if value, ok := captionsJSON["defaultAudioTrackIndex"].(float64); ok {
+		audioIndex = int(value)
+	}
+	if audioIndex < 0 || audioIndex >= len(audioTracks) {
+		return -1
+	}
+	if value, ok := jsonPath(audioTracks[audioIndex], "defaultCaptionTrackIndex").(float64); ok {
+		return int(value)
+	}
+	return -1
+}
+
 // BuildTranscriptList 从 JSON 数据构建 TranscriptList
 func BuildTranscriptList(httpClient *HTTPClient, videoID string, videoDetailsJSON map[string]interface{}, captionsJSON map[string]interface{}) (*TranscriptList, error) {
+	chapters := chaptersFromVideoDetails(videoDetailsJSON)
+
 	// 解析翻译语言
 	var translationLanguages []TranslationLanguage
 	if translationLangs, ok := captionsJSON["translationLanguages"].([]interface{}); ok {
@@ -227,13 +593,13 @@ func BuildTranscriptList(httpClient *HTTPClient, videoID string, videoDetailsJSO
 
 	manuallyCreatedTranscripts := make(map[string]*Transcript)
 	generatedTranscripts := make(map[string]*Transcript)
+	defaultIndex := defaultCaptionTrackIndex(captionsJSON)
 
 	// 解析字幕轨道
 	if captionTracks, ok := captionsJSON["captionTracks"].([]interface{}); ok {
-		for _, caption := range captionTracks {
+		for index, caption := range captionTracks {
 			if captionMap, ok := caption.(map[string]interface{}); ok {
-				kind, _ := captionMap["kind"].(string)
-				isGenerated := kind == "asr"
+				isGenerated := isASRCaptionTrack(captionMap)
 
 				var transcriptDict map[string]*Transcript
 				if isGenerated {
@@ -268,8 +634,9 @@ func BuildTranscriptList(httpClient *HTTPClient, videoID string, videoDetailsJSO
 
 				// 检查是否可翻译
 				var translationLangs []TranslationLanguage
-				if isTranslatable, ok := captionMap["isTranslatable"].(bool); ok && isTranslatable {
-					translationLangs = translationLanguages
+				if jsonBool(captionMap["isTranslatable"]) {
+					// 每条轨道持有自己的副本，避免修改一条轨道的翻译语言影响其他轨道
+					translationLangs = append([]TranslationLanguage(nil), translationLanguages...)
 				}
 
 				transcriptDict[languageCode] = NewTranscript(
@@ -283,6 +650,8 @@ func BuildTranscriptList(httpClient *HTTPClient, videoID string, videoDetailsJSO
 					isGenerated,
 					translationLangs,
 				)
+				transcriptDict[languageCode].chapters = chapters
+				transcriptDict[languageCode].IsDefault = index == defaultIndex
 			}
 		}
 	}
@@ -304,6 +673,97 @@ func (tl *TranscriptList) FindTranscript(languageCodes []string) (*Transcript, e
 	return tl.findTranscript(languageCodes, transcriptDicts)
 }
 
+// FindTranscriptOrTranslate 与 FindTranscript 相同，但在请求的语言都没有字幕时，
+// 会选择一个可翻译的字幕（优先手动创建）并翻译为 languageCodes 中的第一个语言。
+// 没有任何可翻译字幕支持该目标语言时返回 TranslationLanguageNotAvailable
+func (tl *TranscriptList) FindTranscriptOrTranslate(languageCodes []string) (*Transcript, error) {
+	transcript, err := tl.FindTranscript(languageCodes)
+	if _, notFound := err.(*NoTranscriptFound); !notFound || len(languageCodes) == 0 {
+		return transcript, err
+	}
+
+	transcriptDicts := []map[string]*Transcript{
+		tl.manuallyCreatedTranscripts,
+		tl.generatedTranscripts,
+	}
+	if translated := tl.findAutoTranslated(languageCodes[0], transcriptDicts); translated != nil {
+		return translated, nil
+	}
+	return nil, NewTranslationLanguageNotAvailable(tl.VideoID)
+}
+
+// MatchKind 描述 FindTranscriptFuzzy 找到的字幕与请求语言的匹配方式
+type MatchKind string
+
+const (
+	// MatchExact 语言代码完全一致（不区分大小写）
+	MatchExact MatchKind = "exact"
+	// MatchBaseLanguage 请求带地区的代码，匹配到基础语言，如 "en-US" → "en"
+	MatchBaseLanguage MatchKind = "base-language"
+	// MatchRegionFallback 匹配到同一基础语言的其他地区，如 "en" → "en-GB"、"pt-PT" → "pt-BR"
+	MatchRegionFallback MatchKind = "region-fallback"
+	// MatchTranslation 请求的语言没有字幕，由其他字幕翻译得到
+	MatchTranslation MatchKind = "translation"
+)
+
+// LanguageMatch FindTranscriptFuzzy 的结果
+type LanguageMatch struct {
+	Transcript *Transcript
+	// RequestedLanguageCode 命中的请求语言代码
+	RequestedLanguageCode string
+	Kind                  MatchKind
+}
+
+// Exact 判断是否为完全匹配，对匹配质量敏感的调用方可以据此拒绝非精确匹配
+func (m *LanguageMatch) Exact() bool {
+	return m.Kind == MatchExact
+}
+
+// FindTranscriptFuzzy 按 languageCodes 的顺序查找字幕，允许语言代码不完全一致，并返回匹配方式
+// 对每个请求语言依次尝试：完全匹配、基础语言（"en-US" → "en"）、同一语言的其他地区（"en" → "en-GB"），
+// 每一级都优先使用手动创建的字幕；所有请求语言都没有字幕时，尝试将可翻译的字幕翻译为第一个请求语言。
+// 都失败时返回 NoTranscriptFound
+func (tl *TranscriptList) FindTranscriptFuzzy(languageCodes []string) (*LanguageMatch, error) {
+	transcriptDicts := []map[string]*Transcript{
+		tl.manuallyCreatedTranscripts,
+		tl.generatedTranscripts,
+	}
+
+	for _, languageCode := range languageCodes {
+		requested := strings.ToLower(strings.ReplaceAll(languageCode, "_", "-"))
+		base, _, hasRegion := strings.Cut(requested, "-")
+
+		levels := []struct {
+			kind    MatchKind
+			matches func(code string) bool
+		}{
+			{MatchExact, func(code string) bool { return code == requested }},
+			{MatchBaseLanguage, func(code string) bool { return hasRegion && code == base }},
+			{MatchRegionFallback, func(code string) bool { return code != requested && strings.HasPrefix(code, base+"-") }},
+		}
+		for _, level := range levels {
+			for _, transcriptDict := range transcriptDicts {
+				for _, transcript := range sortedTranscripts(transcriptDict) {
+					if level.matches(strings.ToLower(transcript.LanguageCode)) {
+						return &LanguageMatch{Transcript: transcript, RequestedLanguageCode: languageCode, Kind: level.kind}, nil
+					}
+				}
+			}
+		}
+	}
+
+	if len(languageCodes) > 0 {
+		// 目标语言已有字幕时在上面已经完全匹配，这里得到的一定是翻译
+		if translated := tl.findAutoTranslated(languageCodes[0], transcriptDicts); translated != nil {
+			return &LanguageMatch{Transcript: translated, RequestedLanguageCode: languageCodes[0], Kind: MatchTranslation}, nil
+		}
+	}
+
+	notFound := NewNoTranscriptFound(tl.VideoID, languageCodes, tl)
+	notFound.TranslatableTo = tl.translatableTo(languageCodes, transcriptDicts)
+	return nil, notFound
+}
+
 // FindManuallyCreatedTranscript 仅查找手动创建的字幕
 func (tl *TranscriptList) FindManuallyCreatedTranscript(languageCodes []string) (*Transcript, error) {
 	transcriptDicts := []map[string]*Transcript{
@@ -320,15 +780,273 @@ func (tl *TranscriptList) FindGeneratedTranscript(languageCodes []string) (*Tran
 	return tl.findTranscript(languageCodes, transcriptDicts)
 }
 
+// TranscriptKind 字幕的类型：手动创建或自动生成
+type TranscriptKind string
+
+const (
+	TranscriptKindManual    TranscriptKind = "manual"
+	TranscriptKindGenerated TranscriptKind = "generated"
+)
+
+// FindTranscriptStrict 严格按调用方指定的条件查找字幕：语言代码必须完全一致（区分大小写，不做地区回退或翻译，
+// 也不处理 "auto-translate:" 指令），类型必须是 kinds 之一。按 languageCodes 的顺序查找，同一语言按 kinds 的顺序查找；
+// 未传入 kinds 时只查找手动创建的字幕，不会回退到自动生成的字幕。找不到时返回 NoTranscriptFound
+func (tl *TranscriptList) FindTranscriptStrict(languageCodes []string, kinds ...TranscriptKind) (*Transcript, error) {
+	if len(kinds) == 0 {
+		kinds = []TranscriptKind{TranscriptKindManual}
+	}
+	var transcriptDicts []map[string]*Transcript
+	for _, kind := range kinds {
+		switch kind {
+		case TranscriptKindManual:
+			transcriptDicts = append(transcriptDicts, tl.manuallyCreatedTranscripts)
+		case TranscriptKindGenerated:
+			transcriptDicts = append(transcriptDicts, tl.generatedTranscripts)
+		}
+	}
+
+	for _, languageCode := range languageCodes {
+		for _, transcriptDict := range transcriptDicts {
+			if transcript, ok := transcriptDict[languageCode]; ok {
+				return transcript, nil
+			}
+		}
+	}
+	notFound := NewNoTranscriptFound(tl.VideoID, languageCodes, tl)
+	notFound.TranslatableTo = tl.translatableTo(languageCodes, transcriptDicts)
+	return nil, notFound
+}
+
+// DefaultTranscript 返回视频的主字幕：优先使用 YouTube 标记为默认的轨道（IsDefault），
+// 没有时依次使用第一个手动创建的字幕和第一个自动生成的字幕（按语言代码排序，保证结果稳定）。列表为空时返回 NoTranscriptFound
+func (tl *TranscriptList) DefaultTranscript() (*Transcript, error) {
+	transcriptDicts := []map[string]*Transcript{tl.manuallyCreatedTranscripts, tl.generatedTranscripts}
+	for _, transcriptDict := range transcriptDicts {
+		for _, transcript := range sortedTranscripts(transcriptDict) {
+			if transcript.IsDefault {
+				return transcript, nil
+			}
+		}
+	}
+	for _, transcriptDict := range transcriptDicts {
+		if transcripts := sortedTranscripts(transcriptDict); len(transcripts) > 0 {
+			return transcripts[0], nil
+		}
+	}
+	return nil, NewNoTranscriptFound(tl.VideoID, nil, tl)
+}
+
+// AutoTranslatePrefix 语言列表中的翻译指令前缀，如 "auto-translate:en" 表示将任意可翻译的字幕翻译为英语
+const AutoTranslatePrefix = "auto-translate:"
+
 func (tl *TranscriptList) findTranscript(languageCodes []string, transcriptDicts []map[string]*Transcript) (*Transcript, error) {
 	for _, languageCode := range languageCodes {
+		if strings.HasPrefix(languageCode, AutoTranslatePrefix) {
+			if transcript := tl.findAutoTranslated(strings.TrimPrefix(languageCode, AutoTranslatePrefix), transcriptDicts); transcript != nil {
+				return transcript, nil
+			}
+			continue
+		}
 		for _, transcriptDict := range transcriptDicts {
 			if transcript, ok := transcriptDict[languageCode]; ok {
 				return transcript, nil
 			}
 		}
 	}
-	return nil, NewNoTranscriptFound(tl.VideoID, languageCodes, tl)
+	notFound := NewNoTranscriptFound(tl.VideoID, languageCodes, tl)
+	notFound.TranslatableTo = tl.translatableTo(languageCodes, transcriptDicts)
+	return nil, notFound
+}
+
+// translatableTo 返回 languageCodes 中可以由 transcriptDicts 里的字幕翻译得到的语言代码
+func (tl *TranscriptList) translatableTo(languageCodes []string, transcriptDicts []map[string]*Transcript) []string {
+	var result []string
+	for _, languageCode := range languageCodes {
+		languageCode = strings.TrimPrefix(languageCode, AutoTranslatePrefix)
+	search:
+		for _, transcriptDict := range transcriptDicts {
+			for _, transcript := range transcriptDict {
+				if _, ok := transcript.translationLanguagesMap[languageCode]; ok {
+					result = append(result, languageCode)
+					break search
+				}
+			}
+		}
+	}
+	return result
+}
+
+// findAutoTranslated 处理翻译指令：目标语言已有字幕时直接返回，否则将第一个可翻译的字幕翻译为目标语言
+func (tl *TranscriptList) findAutoTranslated(targetLanguageCode string, transcriptDicts []map[string]*Transcript) *Transcript {
+	for _, transcriptDict := range transcriptDicts {
+		if transcript, ok := transcriptDict[targetLanguageCode]; ok {
+			return transcript
+		}
+	}
+	for _, transcriptDict := range transcriptDicts {
+		for _, transcript := range sortedTranscripts(transcriptDict) {
+			if translated, err := transcript.Translate(targetLanguageCode); err == nil {
+				return translated
+			}
+		}
+	}
+	return nil
+}
+
+// AvailableLanguage 表示一条可用字幕轨道的语言信息
+type AvailableLanguage struct {
+	Language       string
+	LanguageCode   string
+	IsGenerated    bool
+	IsTranslatable bool
+}
+
+// AvailableLanguages 返回所有可用字幕轨道的语言信息
+// 手动创建的字幕在前，自动生成的字幕在后，各自按语言代码排序
+func (tl *TranscriptList) AvailableLanguages() []AvailableLanguage {
+	var result []AvailableLanguage
+	for _, transcriptDict := range []map[string]*Transcript{tl.manuallyCreatedTranscripts, tl.generatedTranscripts} {
+		for _, transcript := range sortedTranscripts(transcriptDict) {
+			result = append(result, AvailableLanguage{
+				Language:       transcript.Language,
+				LanguageCode:   transcript.LanguageCode,
+				IsGenerated:    transcript.IsGenerated,
+				IsTranslatable: transcript.IsTranslatable(),
+			})
+		}
+	}
+	return result
+}
+
+// sortedTranscripts 按语言代码排序返回字幕，保证输出顺序稳定
+func sortedTranscripts(transcripts map[string]*Transcript) []*Transcript {
+	languageCodes := make([]string, 0, len(transcripts))
+	for languageCode := range transcripts {
+		languageCodes = append(languageCodes, languageCode)
+	}
+	sort.Strings(languageCodes)
+
+	result := make([]*Transcript, 0, len(languageCodes))
+	for _, languageCode := range languageCodes {
+		result = append(result, transcripts[languageCode])
+	}
+	return result
+}
+
+// TranslationLanguages 返回该视频可翻译的目标语言列表（副本，修改不会影响 TranscriptList）
+// 该列表来自字幕列表级别的 translationLanguages，与各字幕轨道的 isTranslatable 无关：
+// 即使所有轨道都不可翻译（IsTranslatable 为 false），这里仍会返回 YouTube 给出的目标语言。
+// 能否翻译以轨道为准，YouTube 只接受对 isTranslatable 轨道的翻译请求，实际可用的目标语言参见 AllTranslationLanguageCodes
+func (tl *TranscriptList) TranslationLanguages() []TranslationLanguage {
+	result := make([]TranslationLanguage, len(tl.translationLanguages))
+	copy(result, tl.translationLanguages)
+	return result
+}
+
+// LanguageAvailability 某个语言代码下可用的字幕轨道类型
+type LanguageAvailability struct {
+	Manual    bool
+	Generated bool
+}
+
+// LanguageMatrix 返回每个语言代码下是否有手动创建 / 自动生成的字幕
+// map 的遍历顺序不固定，需要稳定顺序时配合 LanguageCodes 使用
+func (tl *TranscriptList) LanguageMatrix() map[string]LanguageAvailability {
+	matrix := make(map[string]LanguageAvailability)
+	for languageCode := range tl.manuallyCreatedTranscripts {
+		availability := matrix[languageCode]
+		availability.Manual = true
+		matrix[languageCode] = availability
+	}
+	for languageCode := range tl.generatedTranscripts {
+		availability := matrix[languageCode]
+		availability.Generated = true
+		matrix[languageCode] = availability
+	}
+	return matrix
+}
+
+// LanguageCodes 返回所有有字幕轨道的语言代码（去重并排序）
+func (tl *TranscriptList) LanguageCodes() []string {
+	matrix := tl.LanguageMatrix()
+	codes := make([]string, 0, len(matrix))
+	for code := range matrix {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// AllTranslationLanguageCodes 返回所有字幕轨道可以翻译到的语言代码的并集（已排序）
+func (tl *TranscriptList) AllTranslationLanguageCodes() []string {
+	seen := make(map[string]bool)
+	for _, transcriptDict := range []map[string]*Transcript{tl.manuallyCreatedTranscripts, tl.generatedTranscripts} {
+		for _, transcript := range transcriptDict {
+			for _, language := range transcript.TranslationLanguages {
+				seen[language.LanguageCode] = true
+			}
+		}
+	}
+
+	codes := make([]string, 0, len(seen))
+	for code := range seen {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// languageJSON TranscriptList JSON 中的单个语言
+type languageJSON struct {
+	LanguageCode   string `json:"language_code"`
+	Language       string `json:"language"`
+	IsTranslatable bool   `json:"is_translatable,omitempty"`
+}
+
+// transcriptListJSON TranscriptList 的 JSON 结构
+type transcriptListJSON struct {
+	VideoID              string         `json:"video_id"`
+	Manual               []languageJSON `json:"manual"`
+	Generated            []languageJSON `json:"generated"`
+	TranslationLanguages []languageJSON `json:"translation_languages"`
+}
+
+// MarshalJSON 将字幕列表序列化为 {"video_id", "manual", "generated", "translation_languages"}，
+// 每种字幕类型和翻译语言都按语言代码排序，相同的列表总是得到相同的输出，可以用作缓存内容或缓存键。
+// 只包含可用语言信息，不包含字幕 URL，无法反序列化为可以 Fetch 的 TranscriptList
+func (tl *TranscriptList) MarshalJSON() ([]byte, error) {
+	data := transcriptListJSON{
+		VideoID:              tl.VideoID,
+		Manual:               []languageJSON{},
+		Generated:            []languageJSON{},
+		TranslationLanguages: []languageJSON{},
+	}
+
+	for _, language := range tl.AvailableLanguages() {
+		item := languageJSON{
+			LanguageCode:   language.LanguageCode,
+			Language:       language.Language,
+			IsTranslatable: language.IsTranslatable,
+		}
+		if language.IsGenerated {
+			data.Generated = append(data.Generated, item)
+		} else {
+			data.Manual = append(data.Manual, item)
+		}
+	}
+
+	translationLanguages := tl.TranslationLanguages()
+	sort.SliceStable(translationLanguages, func(i, j int) bool {
+		return translationLanguages[i].LanguageCode < translationLanguages[j].LanguageCode
+	})
+	for _, language := range translationLanguages {
+		data.TranslationLanguages = append(data.TranslationLanguages, languageJSON{
+			LanguageCode: language.LanguageCode,
+			Language:     language.Language,
+		})
+	}
+
+	return json.Marshal(data)
 }
 
 // String 返回字符串表示
@@ -389,6 +1107,21 @@ const (
 type TranscriptListFetcher struct {
 	httpClient  *HTTPClient
 	proxyConfig ProxyConfig
+	options     *apiOptions
+	stats       FetchStats
+	// retryStart 本次获取字幕列表开始的时间，用于计算获取列表过程中重试的 RetryBudget
+	retryStart time.Time
+}
+
+// FetchStats 最近一次获取字幕列表过程中的诊断信息
+type FetchStats struct {
+	ConsentCookieRequired bool // 是否遇到了同意 Cookie 页面（常见于欧盟地区）
+	ConsentCookieCreated  bool // 是否成功创建了同意 Cookie 并通过了同意页面
+}
+
+// LastStats 返回最近一次 Fetch 的诊断信息（无论成功与否）
+func (tlf *TranscriptListFetcher) LastStats() FetchStats {
+	return tlf.stats
 }
 
 // NewTranscriptListFetcher 创建新的 TranscriptListFetcher
@@ -396,36 +1129,110 @@ func NewTranscriptListFetcher(httpClient *HTTPClient, proxyConfig ProxyConfig) *
 	return &TranscriptListFetcher{
 		httpClient:  httpClient,
 		proxyConfig: proxyConfig,
+		options:     newAPIOptions(nil),
 	}
 }
 
 // Fetch 获取视频的字幕列表
 func (tlf *TranscriptListFetcher) Fetch(videoID string) (*TranscriptList, error) {
-	videoDetailsJSON, captionsJSON, err := tlf.fetchVideoDetailsAndCaptionsJSON(videoID, 0)
+	return tlf.FetchContext(context.Background(), videoID)
+}
+
+// FetchContext 获取视频的字幕列表，ctx 取消时中止请求
+func (tlf *TranscriptListFetcher) FetchContext(ctx context.Context, videoID string) (*TranscriptList, error) {
+	if tlf.httpClient.isClosed() {
+		return nil, ErrClientClosed
+	}
+	ctx = withRequestVideoID(ctx, videoID)
+	tlf.stats = FetchStats{}
+	tlf.retryStart = time.Now()
+
+	videoDetailsJSON, captionsJSON, err := tlf.fetchVideoDetailsAndCaptionsJSON(ctx, videoID, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	transcriptList, err := BuildTranscriptList(tlf.httpClient, videoID, videoDetailsJSON, captionsJSON)
 	if err != nil {
 		return nil, err
 	}
+	transcriptList.setCaptionOptions(tlf.options.captionOptions())
+
+	if tlf.options.refreshOnForbidden {
+		transcriptList.bindURLRefresh(func(ctx context.Context) (*TranscriptList, error) {
+			return tlf.FetchContext(ctx, videoID)
+		})
+	}
+	return transcriptList, nil
+}
+
+// FetchPlayerResponseContext 获取视频的原始 Innertube player 响应，不检查可播放性和字幕数据
+func (tlf *TranscriptListFetcher) FetchPlayerResponseContext(ctx context.Context, videoID string) (map[string]interface{}, error) {
+	tlf.stats = FetchStats{}
 
-	return BuildTranscriptList(tlf.httpClient, videoID, videoDetailsJSON, captionsJSON)
+	innertubeData, _, err := tlf.fetchPlayerResponse(ctx, videoID)
+	return innertubeData, err
 }
 
-func (tlf *TranscriptListFetcher) fetchVideoDetailsAndCaptionsJSON(videoID string, tryNumber int) (map[string]interface{}, map[string]interface{}, error) {
-	html, err := tlf.fetchVideoHTML(videoID)
+// fetchPlayerResponse 请求视频页面提取 API Key，再使用第一个客户端请求 Innertube player 接口，返回解码后的响应和 API Key
+func (tlf *TranscriptListFetcher) fetchPlayerResponse(ctx context.Context, videoID string) (map[string]interface{}, string, error) {
+	ctx = withRequestVideoID(ctx, videoID)
+	html, apiKey, err := tlf.fetchWatchPage(ctx, videoID)
 	if err != nil {
-		return nil, nil, err
+		return nil, "", err
+	}
+
+	innertubeData, err := tlf.fetchInnertubeData(ctx, videoID, apiKey, tlf.requestClient(tlf.options.clients()[0], html))
+	if err != nil {
+		return nil, "", err
+	}
+	return innertubeData, apiKey, nil
+}
+
+// fetchWatchPage 请求视频页面并提取 INNERTUBE_API_KEY
+func (tlf *TranscriptListFetcher) fetchWatchPage(ctx context.Context, videoID string) (string, string, error) {
+	html, err := tlf.fetchVideoHTML(ctx, videoID)
+	if err != nil {
+		return "", "", err
 	}
 
 	apiKey, err := tlf.extractInnertubeAPIKey(html, videoID)
 	if err != nil {
-		return nil, nil, err
+		return "", "", err
+	}
+	return html, apiKey, nil
+}
+
+// requestClient 返回实际请求时使用的客户端（开启 WithClientVersionDetection 时使用视频页面中的版本）
+func (tlf *TranscriptListFetcher) requestClient(client InnertubeClient, html string) InnertubeClient {
+	if tlf.options.detectVersion {
+		return client.withDetectedVersion(html)
 	}
+	return client
+}
 
-	innertubeData, err := tlf.fetchInnertubeData(videoID, apiKey)
+// retryBudgetAllows 判断等待 delay 后重新获取字幕列表是否仍在 HTTPClient.RetryBudget 之内
+func (tlf *TranscriptListFetcher) retryBudgetAllows(delay time.Duration) bool {
+	budget := tlf.httpClient.RetryBudget
+	return budget <= 0 || tlf.retryStart.IsZero() || time.Since(tlf.retryStart)+delay <= budget
+}
+
+// requestBlockedRetryDelay 请求被阻止后重试前的等待时间单位，第 n 次重试前等待 n 倍
+var requestBlockedRetryDelay = time.Second
+
+func (tlf *TranscriptListFetcher) fetchVideoDetailsAndCaptionsJSON(ctx context.Context, videoID string, tryNumber int) (map[string]interface{}, map[string]interface{}, error) {
+	html, apiKey, err := tlf.fetchWatchPage(ctx, videoID)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	videoDetailsJSON, captionsJSON, err := tlf.extractVideoDetailsAndCaptionsJSON(innertubeData, videoID)
+	videoDetailsJSON, captionsJSON, err := tlf.fetchWithClientFallback(ctx, videoID, html, apiKey)
+	if _, ok := err.(*AgeRestricted); ok && tlf.options.embedFallback {
+		// 年龄限制时尝试嵌入式播放器，失败则保留原始错误
+		if details, captions, embedErr := tlf.fetchEmbeddedVideoDetailsAndCaptionsJSON(ctx, videoID, apiKey); embedErr == nil {
+			videoDetailsJSON, captionsJSON, err = details, captions, nil
+		}
+	}
 	if err != nil {
 		// 检查是否是 RequestBlocked 错误，如果是且配置了代理，则重试
 		if requestBlocked, ok := err.(*RequestBlocked); ok {
@@ -433,19 +1240,83 @@ func (tlf *TranscriptListFetcher) fetchVideoDetailsAndCaptionsJSON(videoID strin
 			if tlf.proxyConfig != nil {
 				retries = tlf.proxyConfig.RetriesWhenBlocked()
 			}
-			if tryNumber+1 < retries {
+			// retries 为最大尝试次数（包括第一次请求）
+			delay := requestBlockedRetryDelay * time.Duration(tryNumber+1)
+			if tryNumber+1 < retries && tlf.retryBudgetAllows(delay) {
 				// 等待一小段时间后重试（触发 IP 轮换）
-				time.Sleep(time.Second * time.Duration(tryNumber+1))
-				return tlf.fetchVideoDetailsAndCaptionsJSON(videoID, tryNumber+1)
+				select {
+				case <-time.After(delay):
+				case <-ctx.Done():
+					return nil, nil, NewYouTubeRequestFailed(videoID, ctx.Err())
+				}
+				return tlf.fetchVideoDetailsAndCaptionsJSON(ctx, videoID, tryNumber+1)
 			}
 			return nil, nil, requestBlocked.WithProxyConfig(tlf.proxyConfig)
 		}
+		// 配置为可重试的播放状态错误（如短暂的 "currently unavailable"）
+		if unplayable, ok := err.(*VideoUnplayable); ok && tlf.options.playabilityRetryIf != nil &&
+			tryNumber < tlf.httpClient.MaxRetries && tlf.options.playabilityRetryIf(unplayable) &&
+			tlf.retryBudgetAllows(tlf.httpClient.retryBackoff(tryNumber)) {
+			if err := tlf.httpClient.sleepBackoff(ctx, tryNumber); err != nil {
+				return nil, nil, NewYouTubeRequestFailed(videoID, err)
+			}
+			return tlf.fetchVideoDetailsAndCaptionsJSON(ctx, videoID, tryNumber+1)
+		}
 		return nil, nil, err
 	}
 
 	return videoDetailsJSON, captionsJSON, nil
 }
 
+// fetchWithClientFallback 依次使用 WithClientFallback 配置的客户端请求 Innertube，返回第一个可用的结果
+// 除最后一个客户端外，所有字幕轨道都需要 PO Token 的结果也视为不可用；全部失败时返回最后一个客户端的错误
+func (tlf *TranscriptListFetcher) fetchWithClientFallback(ctx context.Context, videoID, html, apiKey string) (map[string]interface{}, map[string]interface{}, error) {
+	clients := tlf.options.clients()
+
+	var videoDetailsJSON, captionsJSON map[string]interface{}
+	var err error
+	for i, client := range clients {
+		var innertubeData map[string]interface{}
+		innertubeData, err = tlf.fetchInnertubeData(ctx, videoID, apiKey, tlf.requestClient(client, html))
+		if err == nil {
+			videoDetailsJSON, captionsJSON, err = tlf.extractVideoDetailsAndCaptionsJSON(innertubeData, videoID)
+		}
+		if err == nil && i < len(clients)-1 && captionsRequirePoToken(captionsJSON) {
+			err = NewPoTokenRequired(videoID)
+		}
+		if err == nil || ctx.Err() != nil {
+			break
+		}
+	}
+	return videoDetailsJSON, captionsJSON, err
+}
+
+// captionsRequirePoToken 检查是否所有字幕轨道的 URL 都需要 PO Token（包含 "&exp=xpe"）
+func captionsRequirePoToken(captionsJSON map[string]interface{}) bool {
+	captionTracks, _ := captionsJSON["captionTracks"].([]interface{})
+	if len(captionTracks) == 0 {
+		return false
+	}
+	for _, track := range captionTracks {
+		trackMap, _ := track.(map[string]interface{})
+		baseURL, _ := trackMap["baseUrl"].(string)
+		if !strings.Contains(baseURL, "&exp=xpe") {
+			return false
+		}
+	}
+	return true
+}
+
+// fetchEmbeddedVideoDetailsAndCaptionsJSON 以嵌入式播放器客户端请求 InnerTube 数据
+func (tlf *TranscriptListFetcher) fetchEmbeddedVideoDetailsAndCaptionsJSON(ctx context.Context, videoID, apiKey string) (map[string]interface{}, map[string]interface{}, error) {
+	innertubeData, err := tlf.fetchInnertubeData(ctx, videoID, apiKey, InnertubeClientEmbedded)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return tlf.extractVideoDetailsAndCaptionsJSON(innertubeData, videoID)
+}
+
 func (tlf *TranscriptListFetcher) extractInnertubeAPIKey(html, videoID string) (string, error) {
 	pattern := regexp.MustCompile(`"INNERTUBE_API_KEY":\s*"([a-zA-Z0-9_-]+)"`)
 	matches := pattern.FindStringSubmatch(html)
@@ -453,7 +1324,7 @@ func (tlf *TranscriptListFetcher) extractInnertubeAPIKey(html, videoID string) (
 		return matches[1], nil
 	}
 
-	if strings.Contains(html, `class="g-recaptcha"`) {
+	if isCaptchaPage(html) {
 		return "", NewIpBlocked(videoID)
 	}
 
@@ -537,7 +1408,10 @@ func (tlf *TranscriptListFetcher) assertPlayability(innertubeData map[string]int
 		}
 	}
 
-	return NewVideoUnplayable(videoID, reason, subReasons)
+	unplayable := NewVideoUnplayable(videoID, reason, subReasons)
+	unplayable.Status = status
+	unplayable.ErrorScreen, _ = playabilityStatusData["errorScreen"].(map[string]interface{})
+	return unplayable
 }
 
 func (tlf *TranscriptListFetcher) createConsentCookie(html, videoID string) error {
@@ -558,31 +1432,62 @@ func (tlf *TranscriptListFetcher) createConsentCookie(html, videoID string) erro
 	return nil
 }
 
-func (tlf *TranscriptListFetcher) fetchVideoHTML(videoID string) (string, error) {
-	html, err := tlf.fetchHTML(videoID)
+func (tlf *TranscriptListFetcher) fetchVideoHTML(ctx context.Context, videoID string) (string, error) {
+	return tlf.fetchPageHTML(ctx, fmt.Sprintf(WatchURLTemplate, videoID), videoID)
+}
+
+// fetchPageHTML 获取 YouTube 页面（视频页面或播放列表页面），需要时完成同意 Cookie 流程，id 用于错误信息
+func (tlf *TranscriptListFetcher) fetchPageHTML(ctx context.Context, pageURL, id string) (string, error) {
+	html, err := tlf.fetchHTML(ctx, pageURL, id)
 	if err != nil {
 		return "", err
 	}
 
 	if strings.Contains(html, `action="https://consent.youtube.com/s"`) {
-		if err := tlf.createConsentCookie(html, videoID); err != nil {
+		tlf.stats.ConsentCookieRequired = true
+		if err := tlf.createConsentCookie(html, id); err != nil {
 			return "", err
 		}
-		html, err = tlf.fetchHTML(videoID)
+		html, err = tlf.fetchHTML(ctx, pageURL, id)
 		if err != nil {
 			return "", err
 		}
 		if strings.Contains(html, `action="https://consent.youtube.com/s"`) {
-			return "", NewFailedToCreateConsentCookie(videoID)
+			return "", NewFailedToCreateConsentCookie(id)
 		}
+		tlf.stats.ConsentCookieCreated = true
+	}
+
+	if isCaptchaPage(html) {
+		return "", NewIpBlocked(id)
 	}
 
 	return html, nil
 }
 
-func (tlf *TranscriptListFetcher) fetchHTML(videoID string) (string, error) {
-	url := fmt.Sprintf(WatchURLTemplate, videoID)
-	resp, err := tlf.httpClient.Get(url)
+// captchaMarkers 验证码 / 异常流量拦截页面的特征字符串
+var captchaMarkers = []string{
+	`class="g-recaptcha"`,
+	"www.google.com/recaptcha",
+	"unusual traffic from your computer network",
+}
+
+// isCaptchaPage 判断响应内容是否为验证码拦截页面
+// 正常的视频页面会包含 INNERTUBE_API_KEY（也可能引用 recaptcha 脚本），因此不视为验证码页面
+func isCaptchaPage(body string) bool {
+	if strings.Contains(body, `"INNERTUBE_API_KEY"`) {
+		return false
+	}
+	for _, marker := range captchaMarkers {
+		if strings.Contains(body, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+func (tlf *TranscriptListFetcher) fetchHTML(ctx context.Context, pageURL, videoID string) (string, error) {
+	resp, err := tlf.httpClient.GetContext(ctx, pageURL)
 	if err != nil {
 		return "", NewYouTubeRequestFailed(videoID, err)
 	}
@@ -592,7 +1497,7 @@ func (tlf *TranscriptListFetcher) fetchHTML(videoID string) (string, error) {
 		return "", err
 	}
 
-	bodyBytes, err := io.ReadAll(resp.Body)
+	bodyBytes, err := tlf.httpClient.readBody(resp.Body)
 	if err != nil {
 		return "", NewYouTubeRequestFailed(videoID, err)
 	}
@@ -600,56 +1505,179 @@ func (tlf *TranscriptListFetcher) fetchHTML(videoID string) (string, error) {
 	return html.UnescapeString(string(bodyBytes)), nil
 }
 
-func (tlf *TranscriptListFetcher) fetchInnertubeData(videoID, apiKey string) (map[string]interface{}, error) {
+func (tlf *TranscriptListFetcher) fetchInnertubeData(ctx context.Context, videoID, apiKey string, client InnertubeClient) (map[string]interface{}, error) {
+	result, _, err := tlf.fetchInnertubeResponse(ctx, videoID, apiKey, client)
+	return result, err
+}
+
+// fetchInnertubeResponse 请求 Innertube player 接口，返回解码后的响应和原始响应体
+func (tlf *TranscriptListFetcher) fetchInnertubeResponse(ctx context.Context, videoID, apiKey string, client InnertubeClient) (map[string]interface{}, []byte, error) {
 	url := fmt.Sprintf(InnertubeAPIURLTemplate, apiKey)
 
 	// 构建请求体
 	requestBody := map[string]interface{}{
-		"context": InnertubeContext["context"],
+		"context": client.requestContext(videoID),
 		"videoId": videoID,
 	}
 
 	jsonData, err := json.Marshal(requestBody)
 	if err != nil {
-		return nil, NewYouTubeRequestFailed(videoID, err)
+		return nil, nil, NewYouTubeRequestFailed(videoID, err)
 	}
 
-	resp, err := tlf.httpClient.Post(url, "application/json", strings.NewReader(string(jsonData)))
+	resp, err := tlf.httpClient.postWithHeaders(ctx, url, "application/json", strings.NewReader(string(jsonData)), client.requestHeaders(videoID))
 	if err != nil {
-		return nil, NewYouTubeRequestFailed(videoID, err)
+		return nil, nil, NewYouTubeRequestFailed(videoID, err)
 	}
 	defer resp.Body.Close()
 
-	if err := raiseHTTPErrors(resp, videoID); err != nil {
-		return nil, err
+	bodyBytes, err := tlf.httpClient.readBody(resp.Body)
+	if err != nil {
+		return nil, nil, NewYouTubeRequestFailed(videoID, err)
 	}
 
+	// 出错时 Innertube 可能返回 {"error": {...}} 而不是 player 数据（状态码可能是 200 也可能是 4xx）
 	var result map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, NewYouTubeRequestFailed(videoID, err)
+	decodeErr := json.Unmarshal(bodyBytes, &result)
+	if decodeErr == nil {
+		if err := innertubeError(result, videoID); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if err := raiseHTTPErrors(resp, videoID); err != nil {
+		return nil, nil, err
+	}
+	if decodeErr != nil {
+		return nil, nil, NewYouTubeRequestFailed(videoID, decodeErr)
 	}
 
-	return result, nil
+	return result, bodyBytes, nil
 }
 
+// innertubeError 检查 Innertube 返回的错误信封，将其转换为对应的错误类型
+// 被限流（429 / RESOURCE_EXHAUSTED）时返回 IpBlocked，其他错误返回包含错误信息的 YouTubeRequestFailed
+func innertubeError(result map[string]interface{}, videoID string) error {
+	envelope, ok := result["error"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	code, _ := envelope["code"].(float64)
+	status, _ := envelope["status"].(string)
+	message, _ := envelope["message"].(string)
+
+	if int(code) == http.StatusTooManyRequests || status == "RESOURCE_EXHAUSTED" {
+		return NewIpBlocked(videoID)
+	}
+	return NewYouTubeRequestFailed(videoID, fmt.Errorf("innertube error %d %s: %s", int(code), status, message))
+}
+
+// TranscriptContentParser 将字幕接口返回的响应体解析为字幕片段，通过 WithTranscriptContentParser 替换内置的 XML 解析器，
+// 用于处理库尚不支持的字幕格式（如 srv3、json3、vtt）。preserveFormatting 为 Fetch 传入的值
+type TranscriptContentParser interface {
+	Parse(raw []byte, preserveFormatting bool) ([]FetchedTranscriptSnippet, error)
+}
+
+// TranscriptContentParserFunc 将普通函数适配为 TranscriptContentParser
+type TranscriptContentParserFunc func(raw []byte, preserveFormatting bool) ([]FetchedTranscriptSnippet, error)
+
+// Parse 调用 f(raw, preserveFormatting)
+func (f TranscriptContentParserFunc) Parse(raw []byte, preserveFormatting bool) ([]FetchedTranscriptSnippet, error) {
+	return f(raw, preserveFormatting)
+}
+
+// DefaultTranscriptContentParser 内置 XML 解析器的 TranscriptContentParser 形式（TranscriptParser 的默认设置），未设置 WithTranscriptContentParser 时使用。
+// *TranscriptParser 的 Parse 接收字符串且 preserveFormatting 在创建时确定，不直接满足该接口，这个适配器是内置解析器对应的实现；
+// 需要其他设置时使用 TranscriptParser.ContentParser。自定义解析器可以在遇到不认识的格式时回退到它
+var DefaultTranscriptContentParser = NewTranscriptParser(false).ContentParser()
+
 // TranscriptParser 字幕解析器
 type TranscriptParser struct {
-	preserveFormatting bool
-	formattingTags     []string
+	preserveFormatting  bool
+	formattingTags      []string
+	lastSnippetDuration float64
+	decodeEntities      bool
+	minDuration         float64
+	stripCueTokens      bool
+	// stripCueTokensSet 是否通过 WithStripCueTokens 显式设置了 stripCueTokens
+	stripCueTokensSet bool
+}
+
+// DefaultLastSnippetDuration 最后一个片段缺少 dur 属性时使用的默认持续时间（秒）
+const DefaultLastSnippetDuration = 2.0
+
+// formattingTags 保留格式时允许保留的 HTML 格式标签
+var formattingTags = []string{
+	"strong", "em", "b", "i", "mark", "small", "del", "ins", "sub", "sup",
 }
 
+// cueTokenRe 匹配文本中内联的位置 / 对齐设置标记，如 "{ALIGN:START}"、"{POSITION:10%}" 以及 ASS 风格的 "{\an8}"
+// 只匹配 "{大写名称:值}" 和 "{\...}" 两种形式，"{laughs}" 等普通花括号文本不受影响
+var cueTokenRe = regexp.MustCompile(`\{(?:[A-Z][A-Z0-9_]*:[^{}\n]*|\\[^{}\n]*)\}`)
+
 // NewTranscriptParser 创建新的字幕解析器
 func NewTranscriptParser(preserveFormatting bool) *TranscriptParser {
 	return &TranscriptParser{
-		preserveFormatting: preserveFormatting,
-		formattingTags: []string{
-			"strong", "em", "b", "i", "mark", "small", "del", "ins", "sub", "sup",
-		},
+		preserveFormatting:  preserveFormatting,
+		formattingTags:      formattingTags,
+		lastSnippetDuration: DefaultLastSnippetDuration,
+		decodeEntities:      true,
+		stripCueTokens:      !preserveFormatting,
 	}
 }
 
+// WithDecodeEntities 设置是否解码片段文本中的 HTML 实体（默认 true）
+// YouTube 返回的文本经过两次转义，XML 解析只会还原一层；设为 false 时保留剩余的实体（如 &amp;、&#39;），
+// 便于重新输出为 XML 时避免重复转义
+func (tp *TranscriptParser) WithDecodeEntities(decode bool) *TranscriptParser {
+	tp.decodeEntities = decode
+	return tp
+}
+
+// WithStripCueTokens 设置是否移除文本中内联的位置 / 对齐设置标记（如 "{ALIGN:START}"、"{\an8}"，与 HTML 标签分开处理）
+// 默认在不保留格式时移除、保留格式时保留；移除后文本为空的片段会被丢弃
+func (tp *TranscriptParser) WithStripCueTokens(strip bool) *TranscriptParser {
+	tp.stripCueTokens = strip
+	tp.stripCueTokensSet = true
+	return tp
+}
+
+// WithLastSnippetDuration 设置最后一个片段缺少 dur 属性时使用的持续时间（秒）
+func (tp *TranscriptParser) WithLastSnippetDuration(duration float64) *TranscriptParser {
+	tp.lastSnippetDuration = duration
+	return tp
+}
+
+// WithMinDuration 设置片段的最短持续时间（秒），解析时丢弃持续时间小于该值的片段（通常是无意义的残留片段，如 dur="0.01"）
+// 缺少 dur 的片段按推算出的持续时间判断；默认 0，保留所有片段
+func (tp *TranscriptParser) WithMinDuration(duration float64) *TranscriptParser {
+	tp.minDuration = duration
+	return tp
+}
+
+// ContentParser 返回使用 tp 当前设置（WithDecodeEntities、WithLastSnippetDuration、WithMinDuration 等）的 TranscriptContentParser，
+// 可以通过 WithTranscriptContentParser 用于 Fetch。每次解析使用 Fetch 传入的 preserveFormatting，
+// 未通过 WithStripCueTokens 显式设置时是否移除 cue 标记也按该值取默认值；之后对 tp 的修改不会影响返回的解析器
+func (tp *TranscriptParser) ContentParser() TranscriptContentParser {
+	settings := *tp
+	return TranscriptContentParserFunc(func(raw []byte, preserveFormatting bool) ([]FetchedTranscriptSnippet, error) {
+		parser := settings
+		parser.preserveFormatting = preserveFormatting
+		if !parser.stripCueTokensSet {
+			parser.stripCueTokens = !preserveFormatting
+		}
+		return parser.Parse(string(raw))
+	})
+}
+
 // Parse 解析 XML 字幕数据
+// 响应体偶尔包含无效的 UTF-8 字节，XML 解析器会因此拒绝整个文档；解析前将无效字节替换为 U+FFFD，只影响出错的片段文本
 func (tp *TranscriptParser) Parse(rawData string) ([]FetchedTranscriptSnippet, error) {
+	if !utf8.ValidString(rawData) {
+		rawData = strings.ToValidUTF8(rawData, "\uFFFD")
+	}
+
 	doc := etree.NewDocument()
 	if err := doc.ReadFromString(rawData); err != nil {
 		return nil, fmt.Errorf("failed to parse XML: %w", err)
@@ -681,7 +1709,9 @@ func (tp *TranscriptParser) Parse(rawData string) ([]FetchedTranscriptSnippet, e
 		fmt.Sscanf(durationStr, "%f", &duration)
 
 		// 处理 HTML 标签
-		text = html.UnescapeString(text)
+		if tp.decodeEntities {
+			text = html.UnescapeString(text)
+		}
 		if !tp.preserveFormatting {
 			// 移除所有 HTML 标签
 			text = tp.removeAllHTMLTags(text)
@@ -689,6 +1719,12 @@ func (tp *TranscriptParser) Parse(rawData string) ([]FetchedTranscriptSnippet, e
 			// 只保留指定的格式标签
 			text = tp.removeNonFormattingHTMLTags(text)
 		}
+		if tp.stripCueTokens && cueTokenRe.MatchString(text) {
+			text = strings.TrimSpace(cueTokenRe.ReplaceAllString(text, ""))
+			if text == "" {
+				continue
+			}
+		}
 
 		snippets = append(snippets, FetchedTranscriptSnippet{
 			Text:     text,
@@ -697,7 +1733,40 @@ func (tp *TranscriptParser) Parse(rawData string) ([]FetchedTranscriptSnippet, e
 		})
 	}
 
-	return snippets, nil
+	tp.inferMissingDurations(snippets)
+
+	return dropShortSnippets(snippets, tp.minDuration), nil
+}
+
+// dropShortSnippets 丢弃持续时间小于 minDuration（秒）的片段，minDuration <= 0 时原样返回
+func dropShortSnippets(snippets []FetchedTranscriptSnippet, minDuration float64) []FetchedTranscriptSnippet {
+	if minDuration <= 0 {
+		return snippets
+	}
+	kept := snippets[:0]
+	for _, snippet := range snippets {
+		if snippet.Duration >= minDuration {
+			kept = append(kept, snippet)
+		}
+	}
+	return kept
+}
+
+// inferMissingDurations 为缺少 dur（或 dur 为 0）的片段推算持续时间：
+// 使用之后第一个开始时间更晚的片段的开始时间减去当前开始时间（跳过开始时间相同的片段），没有这样的片段时使用 lastSnippetDuration
+func (tp *TranscriptParser) inferMissingDurations(snippets []FetchedTranscriptSnippet) {
+	for i := range snippets {
+		if snippets[i].Duration > 0 {
+			continue
+		}
+		snippets[i].Duration = tp.lastSnippetDuration
+		for _, next := range snippets[i+1:] {
+			if gap := next.Start - snippets[i].Start; gap > 0 {
+				snippets[i].Duration = gap
+				break
+			}
+		}
+	}
 }
 
 func (tp *TranscriptParser) removeAllHTMLTags(text string) string {